@@ -0,0 +1,117 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// borderStyle は表の罫線に使う文字
+type borderStyle struct {
+	h, v           string // 横線・縦線
+	tl, tr, bl, br string // 四隅
+	lt, rt         string // 左右の辺から内側へ伸びる分岐（┣ ┫）
+	tt, bt, cross  string // 列の区切りの上端・下端・交差（┳ ┻ ╋）
+	pad            string // 縦線と内容の間
+	sep            string // キーと値の区切り
+	rules          bool   // false なら罫線だけの行を描かない
+	ascii          bool   // 罫線以外の記号（"—"・"…"）も ASCII で書く
+}
+
+// -border に指定できる罫線（compact は枠を使わずに列だけ揃える）
+var borderNames = []string{"heavy", "light", "rounded", "double", "ascii", "none", "compact"}
+
+var borderStyles = map[string]borderStyle{
+	"heavy":   {h: "━", v: "┃", tl: "┏", tr: "┓", bl: "┗", br: "┛", lt: "┣", rt: "┫", tt: "┳", bt: "┻", cross: "╋", pad: " ", sep: " : ", rules: true},
+	"light":   {h: "─", v: "│", tl: "┌", tr: "┐", bl: "└", br: "┘", lt: "├", rt: "┤", tt: "┬", bt: "┴", cross: "┼", pad: " ", sep: " : ", rules: true},
+	"rounded": {h: "─", v: "│", tl: "╭", tr: "╮", bl: "╰", br: "╯", lt: "├", rt: "┤", tt: "┬", bt: "┴", cross: "┼", pad: " ", sep: " : ", rules: true},
+	"double":  {h: "═", v: "║", tl: "╔", tr: "╗", bl: "╚", br: "╝", lt: "╠", rt: "╣", tt: "╦", bt: "╩", cross: "╬", pad: " ", sep: " : ", rules: true},
+	"ascii":   {h: "-", v: "|", tl: "+", tr: "+", bl: "+", br: "+", lt: "+", rt: "+", tt: "+", bt: "+", cross: "+", pad: " ", sep: " : ", rules: true, ascii: true},
+	// none は枠の位置を空白にして、罫線だけの行を省く
+	"none":    {h: " ", v: " ", tl: " ", tr: " ", bl: " ", br: " ", lt: " ", rt: " ", tt: " ", bt: " ", cross: " ", pad: " ", sep: " : "},
+	"compact": {sep: "  "},
+}
+
+// 表の描画に使う罫線（config.json の border / -border。prepare で設定する）
+var tableBorder = borderStyles["heavy"]
+
+// setBorder は名前から罫線を選ぶ
+func setBorder(name string) error {
+	if name == "" {
+		name = "heavy"
+	}
+	b, ok := borderStyles[strings.ToLower(name)]
+	if !ok {
+		return errors.New(msg("err.unknown_border", name, strings.Join(borderNames, ", ")))
+	}
+	tableBorder = b
+	return nil
+}
+
+// setAmbiguousWidth は曖昧幅の文字（罫線・"…"・"—" など）を何桁として数えるかを決める。
+// auto は go-runewidth の判定（RUNEWIDTH_EASTASIAN、なければロケールが CJK か）に従う
+func setAmbiguousWidth(mode string) error {
+	switch strings.ToLower(mode) {
+	case "", "auto":
+		return nil
+	case "narrow":
+		runewidth.DefaultCondition.EastAsianWidth = false
+	case "wide":
+		runewidth.DefaultCondition.EastAsianWidth = true
+	default:
+		return errors.New(msg("err.unknown_ambiguous_width", mode))
+	}
+	runewidth.EastAsianWidth = runewidth.DefaultCondition.EastAsianWidth
+	return nil
+}
+
+// fill は横線を表示幅 n まで並べる（曖昧幅を2桁と数える場合に余る1桁は空白で埋める）
+func (b borderStyle) fill(n int) string {
+	if b.h == "" || n <= 0 {
+		return strings.Repeat(" ", max(n, 0))
+	}
+	w := max(dispWidth(b.h), 1)
+	return strings.Repeat(b.h, n/w) + strings.Repeat(" ", n%w)
+}
+
+// rule は left + 横線 + right の罫線の行を返す。罫線を描かない場合は nil
+func (b borderStyle) rule(left, right string, width int) []string {
+	if !b.rules {
+		return nil
+	}
+	return []string{left + b.fill(width-dispWidth(left)-dispWidth(right)) + right}
+}
+
+// title は表題を中央に置いた行を返す（compact では左寄せ）
+func (b borderStyle) title(s string, width int) string {
+	if b.v == "" {
+		return s
+	}
+	space := max(width-2*dispWidth(b.v)-dispWidth(s), 0)
+	return b.v + strings.Repeat(" ", space/2) + s + strings.Repeat(" ", space-space/2) + b.v
+}
+
+// left / right は内容の行の左右の端
+func (b borderStyle) left() string  { return b.v + b.pad }
+func (b borderStyle) right() string { return b.pad + b.v }
+
+// dash はステータスのコードと説明の間などに置くダッシュ
+func (b borderStyle) dash() string {
+	if b.ascii {
+		return "-"
+	}
+	return "—"
+}
+
+// ellipsis は切り詰めた文字列の末尾に付ける省略記号
+func (b borderStyle) ellipsis() string {
+	if b.ascii {
+		return "..."
+	}
+	return "…"
+}
@@ -0,0 +1,139 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 0 ならキャッシュを使わない（-cache-ttl / config.json cache_ttl）
+var cacheTTL time.Duration
+
+type cacheEntry struct {
+	Server  string    `json:"server"`
+	Query   string    `json:"query"`
+	Fetched time.Time `json:"fetched"`
+	Raw     string    `json:"raw"`
+}
+
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "whois"), nil
+}
+
+func cachePath(server, query string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(server) + "\n" + query))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"), nil
+}
+
+func cacheGet(server, query string) (string, bool) {
+	if cacheTTL <= 0 {
+		return "", false
+	}
+	path, err := cachePath(server, query)
+	if err != nil {
+		return "", false
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var e cacheEntry
+	if json.Unmarshal(b, &e) != nil || time.Since(e.Fetched) > cacheTTL {
+		return "", false
+	}
+	return e.Raw, true
+}
+
+// cachePut は応答を保存する。キャッシュは補助的なものなので失敗は無視する
+func cachePut(server, query, raw string) {
+	if cacheTTL <= 0 {
+		return
+	}
+	path, err := cachePath(server, query)
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(cacheEntry{Server: server, Query: query, Fetched: time.Now(), Raw: raw})
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0o644)
+}
+
+func cacheFiles() ([]string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func cacheEntries() ([]cacheEntry, error) {
+	files, err := cacheFiles()
+	if err != nil {
+		return nil, err
+	}
+	var entries []cacheEntry
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var e cacheEntry
+		if json.Unmarshal(b, &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Fetched.After(entries[j].Fetched) })
+	return entries, nil
+}
+
+// removeCacheEntries はキャッシュを削除する。expiredOnly なら cacheTTL を過ぎたものだけ
+func removeCacheEntries(expiredOnly bool) (int, error) {
+	files, err := cacheFiles()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, f := range files {
+		if expiredOnly {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			var e cacheEntry
+			if json.Unmarshal(b, &e) == nil && time.Since(e.Fetched) <= cacheTTL {
+				continue
+			}
+		}
+		if err := os.Remove(f); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
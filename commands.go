@@ -0,0 +1,782 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1 // 検索・書き込みなど実行時のエラー
+	exitUsage = 2 // 引数・フラグの誤り
+)
+
+const defaultCommand = "lookup"
+
+type command struct {
+	name     string
+	usage    string
+	flags    []func(fs *flag.FlagSet)
+	run      func(args []string, config Config) int
+	examples []string
+	// rateLimit は config の rate_limit が未設定のときに使う間隔（squat・sweep）
+	rateLimit string
+}
+
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	// エラーとヘルプは run / printHelp が表示する
+	fs.SetOutput(io.Discard)
+	for _, add := range c.flags {
+		add(fs)
+	}
+	return fs
+}
+
+// 実行中のコマンドの FlagSet（flagWasSet が参照する）
+var activeFlags *flag.FlagSet
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			name:  "lookup",
+			usage: "whois [lookup] [options] <domain>",
+			flags: []func(*flag.FlagSet){lookupFlags, exportFlags, dateFlags, networkFlags, commonFlags},
+			run:   runLookup,
+			examples: []string{
+				"whois daruks.com",
+				"whois -table minecraft.net",
+				"whois example.org -raw",
+				"whois -o ./output.txt wikipedia.org",
+				"whois -server whois.verisign-grs.com:43 daruks.com",
+				"whois アググン.jp",
+				"whois -follow-handles -table example.co.jp",
+				"whois -jprs-type con XX000JP",
+				"whois -lang en example.jp",
+				"whois -compare example.com example.net example.jp",
+				"whois -field expiry example.com",
+				"whois -q '.nameservers[]' example.com",
+				`whois -format '{{.Domain}} {{.Registrar}} {{.Expiry.Format "2006-01-02"}}' example.com`,
+			},
+		},
+		{
+			name:  "bulk",
+			usage: "whois bulk [options] [-f <file>] [domain ...]",
+			flags: []func(*flag.FlagSet){exportFlags, dateFlags, networkFlags, commonFlags},
+			run:   runBulk,
+			examples: []string{
+				"whois bulk -f domains.txt -o out.csv",
+				"whois bulk -output ndjson example.com example.net",
+				"cat domains.txt | whois bulk -f - -field expiry",
+			},
+		},
+		{
+			name:  "report",
+			usage: "whois report -html <out.html> [-f <file>] [domain ...]",
+			flags: []func(*flag.FlagSet){reportFlags, networkFlags, commonFlags},
+			run:   runReportCommand,
+			examples: []string{
+				"whois report -html out.html -f domains.txt",
+			},
+		},
+		{
+			name:  "tui",
+			usage: "whois tui [options] [-f <file>] [domain ...]",
+			flags: []func(*flag.FlagSet){tuiFlags, networkFlags, dateFlags, commonFlags},
+			run:   runTUICommand,
+			examples: []string{
+				"whois tui -f domains.txt",
+				"whois tui example.com example.net",
+				"whois tui",
+			},
+		},
+		{
+			name:      "squat",
+			usage:     "whois squat [options] <domain>",
+			flags:     []func(*flag.FlagSet){squatFlags, networkFlags, commonFlags},
+			run:       runSquat,
+			rateLimit: defaultCheckRateLimit,
+			examples: []string{
+				"whois squat example.com",
+				"whois squat -dry-run -kinds omission,homoglyph example.com",
+				"whois squat -tlds com,net,jp -output csv -o squat.csv example.com",
+			},
+		},
+		{
+			name:      "sweep",
+			usage:     "whois sweep [options] <label>",
+			flags:     []func(*flag.FlagSet){sweepFlags, networkFlags, commonFlags},
+			run:       runSweep,
+			rateLimit: defaultCheckRateLimit,
+			examples: []string{
+				"whois sweep mybrand",
+				"whois sweep -tlds com,net,jp,io mybrand",
+				"whois sweep -tlds all -output csv -o mybrand.csv mybrand",
+			},
+		},
+		{
+			name:  "confusables",
+			usage: "whois confusables <domain> ...",
+			flags: []func(*flag.FlagSet){commonFlags},
+			run:   runConfusables,
+			examples: []string{
+				"whois confusables уаһоо.com",
+				"whois confusables xn--80ak6aa92e.com",
+			},
+		},
+		{
+			name:  "servers",
+			usage: "whois servers [domain ...]",
+			flags: []func(*flag.FlagSet){commonFlags},
+			run:   runServers,
+			examples: []string{
+				"whois servers",
+				"whois servers example.jp example.dev",
+			},
+		},
+		{
+			name:  "cache",
+			usage: "whois cache [list|clear|prune|path]",
+			flags: []func(*flag.FlagSet){cacheFlags, commonFlags},
+			run:   runCache,
+			examples: []string{
+				"whois cache list",
+				"whois cache prune -cache-ttl 24h",
+				"whois cache clear",
+			},
+		},
+		{
+			name:  "config",
+			usage: "whois config [show|validate|init|profiles|themes]",
+			flags: []func(*flag.FlagSet){configFlags, commonFlags},
+			run:   runConfig,
+			examples: []string{
+				"whois config show",
+				"whois config validate -config ./whois.json",
+				"whois config init",
+				"whois config profiles",
+				"whois config themes",
+			},
+		},
+		{
+			name:  "serve",
+			usage: "whois serve [-addr <host:port>]",
+			flags: []func(*flag.FlagSet){serveFlags, networkFlags, commonFlags},
+			run:   runServe,
+			examples: []string{
+				"whois serve -addr 127.0.0.1:8043",
+				"curl 'http://127.0.0.1:8043/lookup?domain=example.com'",
+			},
+		},
+		{
+			name:  "completion",
+			usage: "whois completion bash|zsh|fish|powershell",
+			flags: []func(*flag.FlagSet){commonFlags},
+			run:   runCompletion,
+			examples: []string{
+				"source <(whois completion bash)",
+				"whois completion zsh > \"${fpath[1]}/_whois\"",
+				"whois completion fish > ~/.config/fish/completions/whois.fish",
+				"whois completion powershell | Out-String | Invoke-Expression",
+			},
+		},
+		{
+			name:  "version",
+			usage: "whois version",
+			flags: []func(*flag.FlagSet){commonFlags},
+			run: func(args []string, config Config) int {
+				printVersion(config.Color)
+				return exitOK
+			},
+		},
+		{
+			name:  "help",
+			usage: "whois help [command]",
+			flags: []func(*flag.FlagSet){commonFlags},
+			run:   runHelp,
+		},
+	}
+}
+
+func commandByName(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func commonFlags(fs *flag.FlagSet) {
+	fs.StringVar(configFlag, "config", "", "Read this config file instead of ./config.json")
+	fs.StringVar(profileFlag, "profile", "", "Apply a named profile from config.json \"profiles\" (also WHOIS_PROFILE)")
+	fs.StringVar(langFlag, "lang", "", "Display language (ja, en, ...), default: config.json lang or $LANG")
+	fs.BoolVar(noColorFlag, "nocolor", false, "Disable colored output")
+	fs.BoolVar(unicodeFlag, "unicode", false, "Show internationalized domain names in Unicode only")
+	fs.BoolVar(punycodeFlag, "punycode", false, "Show internationalized domain names as punycode only")
+	fs.StringVar(borderFlag, "border", "", "Table borders: heavy, light, rounded, double, ascii, none, compact")
+	fs.StringVar(ambiguousFlag, "ambiguous-width", "", "Width of East Asian ambiguous characters: auto, narrow, wide")
+	fs.StringVar(themeFlag, "theme", "", "Color theme: dark, light, high-contrast, monochrome or a config.json \"themes\" name")
+	fs.BoolVar(noPagerFlag, "no-pager", false, "Print long output directly instead of through $PAGER")
+}
+
+func networkFlags(fs *flag.FlagSet) {
+	fs.StringVar(serverFlag, "server", "", "Override WHOIS server host[:port]")
+	fs.DurationVar(timeoutFlag, "timeout", 8*time.Second, "Network timeout (e.g. 5s, 2m)")
+	fs.BoolVar(followFlag, "follow", true, "Follow referral WHOIS server if present")
+	fs.StringVar(jprsTypeFlag, "jprs-type", "", "JPRS query type: dom, net, host, con")
+	fs.BoolVar(followHandlesFlag, "follow-handles", false, "Resolve JPRS contact handles (admin/tech) and show their details")
+	cacheFlags(fs)
+}
+
+func cacheFlags(fs *flag.FlagSet) {
+	fs.StringVar(cacheTTLFlag, "cache-ttl", "", "Reuse cached responses younger than this (e.g. 1h), default: config.json cache_ttl")
+}
+
+func dateFlags(fs *flag.FlagSet) {
+	fs.StringVar(tzFlag, "tz", "", "Time zone for displayed dates (Local, UTC, JST, Asia/Tokyo, ...)")
+	fs.StringVar(dateFormatFlag, "date-format", "", "Go time layout for displayed dates, default: 2006-01-02 15:04:05 MST")
+	fs.BoolVar(relativeFlag, "relative", false, "Append relative time to displayed dates (e.g. in 43 days)")
+}
+
+func exportFlags(fs *flag.FlagSet) {
+	fs.StringVar(outputFlag, "output", "", "Output format: conventional, table, raw, json, csv, tsv, ndjson, yaml, markdown")
+	fs.StringVar(fieldsFlag, "fields", defaultExportFields, "Columns for csv/tsv output (comma separated)")
+	fs.StringVar(fieldFlag, "field", "", "Print only the value of one field (e.g. expiry, nameservers)")
+	fs.StringVar(queryFlag, "q", "", "Extract values with a path expression over the JSON record (e.g. '.nameservers[]')")
+	fs.StringVar(listFileFlag, "f", "", "Read names to look up from a file, one per line (- for stdin)")
+	fs.StringVar(outFile, "o", "", "Output to file")
+	fs.BoolVar(verboseFlag, "verbose", false, "Show empty, redacted and unclassified sections in table output")
+}
+
+func lookupFlags(fs *flag.FlagSet) {
+	fs.BoolVar(rawFlag, "raw", false, "Output raw whois text")
+	fs.BoolVar(tableFlag, "table", false, "Render output as a box-drawn table")
+	fs.IntVar(widthFlag, "width", 0, "Table width (columns), default: 120 or $COLUMNS")
+	fs.BoolVar(compareFlag, "compare", false, "Compare several domains side by side")
+	fs.StringVar(formatFlag, "format", "", "Render the record with a Go text/template string")
+	fs.StringVar(templateFlag, "template", "", "Render the record with a template file or a named template from config.json")
+	fs.BoolVar(versionFlag, "version", false, "Show version information")
+	fs.BoolVar(interactiveFlag, "i", false, "Interactive prompt with history (type :help for commands)")
+}
+
+func configFlags(fs *flag.FlagSet) {
+	fs.BoolVar(forceFlag, "force", false, "Overwrite an existing file with config init")
+}
+
+func reportFlags(fs *flag.FlagSet) {
+	fs.StringVar(htmlFlag, "html", "", "Write an HTML report to this file")
+	fs.StringVar(listFileFlag, "f", "", "Read names from a file, one per line (- for stdin)")
+}
+
+func tuiFlags(fs *flag.FlagSet) {
+	fs.StringVar(listFileFlag, "f", "", "Read names from a file, one per line (- for stdin)")
+	fs.StringVar(fieldsFlag, "fields", defaultExportFields, "Columns for csv/tsv export (comma separated)")
+	fs.BoolVar(verboseFlag, "verbose", false, "Show empty, redacted and unclassified sections")
+}
+
+func squatFlags(fs *flag.FlagSet) {
+	fs.StringVar(kindsFlag, "kinds", strings.Join(squatKinds, ","), "Candidate kinds to generate (comma separated)")
+	fs.StringVar(tldsFlag, "tlds", "", "TLDs for tld-swap (comma separated or all), default: the built-in server table")
+	fs.BoolVar(dryRunFlag, "dry-run", false, "Print the candidates without querying")
+	fs.BoolVar(allFlag, "all", false, "Also list unregistered candidates in the table")
+	fs.StringVar(outputFlag, "output", "", "Output format: table, csv, tsv, ndjson, yaml, markdown")
+	fs.StringVar(fieldsFlag, "fields", squatExportFields, "Columns for csv/tsv output (comma separated)")
+	fs.StringVar(outFile, "o", "", "Output to file")
+}
+
+func sweepFlags(fs *flag.FlagSet) {
+	fs.StringVar(tldsFlag, "tlds", "", "TLDs to check (comma separated or all), default: the built-in server table")
+	fs.StringVar(outputFlag, "output", "", "Output format: table, csv, tsv, ndjson, yaml, markdown")
+	fs.StringVar(fieldsFlag, "fields", sweepExportFields, "Columns for csv/tsv output (comma separated)")
+	fs.StringVar(outFile, "o", "", "Output to file")
+}
+
+func serveFlags(fs *flag.FlagSet) {
+	fs.StringVar(addrFlag, "addr", "127.0.0.1:8043", "Listen address for the HTTP API")
+}
+
+// ヘルプに表示する値の書式
+var flagArgs = map[string]string{
+	"width":           "<n>",
+	"compare":         "<d1> <d2> ...",
+	"output":          "<format>",
+	"fields":          "<list>",
+	"field":           "<name>",
+	"q":               "<path>",
+	"f":               "<file>",
+	"format":          "<template>",
+	"template":        "<file|name>",
+	"o":               "<file>",
+	"server":          "<host[:port]>",
+	"timeout":         "<duration>",
+	"jprs-type":       "<type>",
+	"cache-ttl":       "<duration>",
+	"lang":            "<code>",
+	"tz":              "<zone>",
+	"date-format":     "<layout>",
+	"html":            "<file>",
+	"addr":            "<host:port>",
+	"profile":         "<name>",
+	"theme":           "<name>",
+	"border":          "<style>",
+	"ambiguous-width": "<width>",
+	"kinds":           "<list>",
+	"tlds":            "<list>",
+}
+
+// parseInterspersed は位置引数の後ろにあるフラグも解釈する（whois example.com -raw）。
+// "--" 以降はすべて位置引数として扱う
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// prepare はフラグ解釈後に言語・日付表示・キャッシュ・カラーの設定を確定する
+func prepare(config *Config) int {
+	config.Lang = resolveLang("", config.Lang)
+	setLocale(config.Lang, localeSearchDirs(*config))
+
+	if err := setDateDisplay(config.Timezone, config.DateFormat, config.RelativeDates); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.timezone", config.Timezone, err))
+		return exitUsage
+	}
+
+	if config.CacheTTL != "" {
+		d, err := time.ParseDuration(config.CacheTTL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.cache_ttl", config.CacheTTL, err))
+			return exitUsage
+		}
+		cacheTTL = d
+	}
+
+	// -timeout を指定した場合は overlayConfigFlags で config.Timeout に反映済み
+	if config.Timeout != "" {
+		d, err := time.ParseDuration(config.Timeout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.timeout", config.Timeout, err))
+			return exitUsage
+		}
+		*timeoutFlag = d
+	}
+	if config.RateLimit != "" {
+		d, err := time.ParseDuration(config.RateLimit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.rate_limit", config.RateLimit, err))
+			return exitUsage
+		}
+		rateLimit = d
+	}
+
+	for _, key := range sortedKeys(config.Servers) {
+		if err := validateServerConfig(key, config.Servers[key]); err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.config", err))
+			return exitUsage
+		}
+	}
+	serverOverrides = config.Servers
+
+	switch m := strings.ToLower(config.IDNDisplay); {
+	case m == "":
+	case slices.Contains(idnDisplayModes, m):
+		idnDisplay = m
+	default:
+		fmt.Fprintln(os.Stderr, msg("err.idn_display", config.IDNDisplay))
+		return exitUsage
+	}
+
+	if err := setAmbiguousWidth(config.AmbiguousWidth); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.border", err))
+		return exitUsage
+	}
+	if err := setBorder(config.Border); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.border", err))
+		return exitUsage
+	}
+
+	// 幅を指定しなかった場合は端末の幅を上限に、表を内容に合わせて縮める
+	if _, _, ok := terminalSize(); ok && *widthFlag <= 0 && os.Getenv("COLUMNS") == "" {
+		fitTables = true
+	}
+
+	// -o / NO_COLOR / 非TTY ではカラーを無効にする（-nocolor は設定の color に反映済み）。
+	// FORCE_COLOR は非TTYでも有効にし、色数も指定できる
+	level, forced := forceColorLevel()
+	switch {
+	case *outFile != "" || envNoColor():
+		config.Color = false
+	case forced:
+	case !isStdoutTTY():
+		config.Color = false
+	default:
+		level = terminalColorLevel()
+	}
+	if level == colorNone {
+		config.Color = false
+	}
+	if config.Color {
+		theme, err := loadTheme(config.Theme, config.Themes, level)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.theme", err))
+			return exitUsage
+		}
+		activeTheme, colorLevel = theme, level
+	}
+	return exitOK
+}
+
+// run は先頭の引数でサブコマンドを選び、該当しなければ lookup として扱う
+func run(args []string) int {
+	// 設定を読むまでは環境変数の言語でエラーを表示する
+	setLocale(resolveLang("", ""), localeSearchDirs(Config{}))
+
+	cmd := commandByName(defaultCommand)
+	if len(args) > 0 {
+		if c := commandByName(args[0]); c != nil {
+			cmd, args = c, args[1:]
+		}
+	}
+
+	fs := cmd.flagSet()
+	activeFlags = fs
+	rest, err := parseInterspersed(fs, args)
+	help := errors.Is(err, flag.ErrHelp)
+	if err != nil && !help {
+		fmt.Fprintln(os.Stderr, msg("err.flag", err))
+		fmt.Fprintln(os.Stderr, msg("err.help_hint", cmd.name))
+		return exitUsage
+	}
+
+	// config コマンドは壊れた設定の確認にも使うので読み込みエラーで止めない
+	config, _, err := loadConfig(*configFlag)
+	if err != nil && cmd.name != "config" {
+		fmt.Fprintln(os.Stderr, msg("err.config", err))
+		return exitUsage
+	}
+	if config.RateLimit == "" {
+		config.RateLimit = cmd.rateLimit
+	}
+	if code := prepare(&config); code != exitOK {
+		return code
+	}
+	if help {
+		printHelp(cmd, config.Color)
+		return exitOK
+	}
+	return cmd.run(rest, config)
+}
+
+func runHelp(args []string, config Config) int {
+	cmd := commandByName(defaultCommand)
+	if len(args) > 0 {
+		if cmd = commandByName(args[0]); cmd == nil {
+			fmt.Fprintln(os.Stderr, msg("err.unknown_command", args[0]))
+			return exitUsage
+		}
+	}
+	printHelp(cmd, config.Color)
+	return exitOK
+}
+
+func runBulk(args []string, config Config) int {
+	mode := outputMode(config.DefaultOutput)
+	if !exportFormats[mode] && mode != "query" && mode != "fields" {
+		if flagWasSet("output") {
+			fmt.Fprintln(os.Stderr, msg("err.bulk_format", mode))
+			return exitUsage
+		}
+		mode = "csv"
+	}
+	if len(args) == 0 && *listFileFlag == "" {
+		fmt.Fprintln(os.Stderr, msg("err.bulk_usage"))
+		return exitUsage
+	}
+	failed, err := runExport(mode, args, config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.export", err))
+		return exitError
+	}
+	if failed > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+func runReportCommand(args []string, config Config) int {
+	if *htmlFlag == "" {
+		fmt.Fprintln(os.Stderr, msg("err.report_usage"))
+		return exitUsage
+	}
+	if err := runReport(args, config); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.report", err))
+		return exitError
+	}
+	return exitOK
+}
+
+// servers は組み込みの TLD → WHOIS サーバ表、または指定した名前の問い合わせ先を表示する
+func runServers(args []string, config Config) int {
+	var kvs []KV
+	if len(args) == 0 {
+		// config.json の servers を先に表示し、組み込みの表より優先されることを示す
+		for _, key := range sortedKeys(serverOverrides) {
+			sc := serverOverrides[key]
+			kvs = append(kvs, KV{Key: serverSuffix(key), Val: describeServer(sc.Host, sc) + " ← config"})
+		}
+		for _, s := range whoisServers {
+			kvs = append(kvs, KV{Key: s.suffix, Val: s.server})
+		}
+		kvs = append(kvs, KV{Key: "*", Val: defaultWhoisServer})
+	} else {
+		for _, a := range args {
+			domain, err := normalizeDomain(a)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitUsage
+			}
+			server := getWhoisServer(domain)
+			val := describeServer(server, serverSettings(server, domain))
+			// Host のない設定は IANA の参照先に使う
+			if sc := referralSettings("", server, domain); sc.Query != "" || sc.Charset != "" {
+				val += " → " + describeServer(msg("servers.referred"), sc)
+			}
+			kvs = append(kvs, KV{Key: displayIDN(domain), Val: val})
+		}
+	}
+	output(renderTable(msg("servers.title"), kvs, tableWidth(), config.Color), "")
+	return exitOK
+}
+
+func runCache(args []string, config Config) int {
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	switch action {
+	case "path":
+		dir, err := cacheDir()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.cache", err))
+			return exitError
+		}
+		fmt.Println(dir)
+	case "list":
+		entries, err := cacheEntries()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.cache", err))
+			return exitError
+		}
+		if len(entries) == 0 {
+			fmt.Println(msg("cache.empty"))
+			return exitOK
+		}
+		var kvs []KV
+		for _, e := range entries {
+			age := time.Since(e.Fetched).Round(time.Second)
+			kvs = append(kvs, KV{Key: e.Query, Val: msg("cache.entry", e.Server, age)})
+		}
+		output(renderTable(msg("cache.title"), kvs, tableWidth(), config.Color), "")
+	case "clear", "prune":
+		if action == "prune" && cacheTTL <= 0 {
+			fmt.Fprintln(os.Stderr, msg("err.cache_prune"))
+			return exitUsage
+		}
+		n, err := removeCacheEntries(action == "prune")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.cache", err))
+			return exitError
+		}
+		fmt.Println(msg("cache.removed", n))
+	default:
+		fmt.Fprintln(os.Stderr, msg("err.unknown_command", "cache "+action))
+		fmt.Fprintln(os.Stderr, msg("err.help_hint", "cache"))
+		return exitUsage
+	}
+	return exitOK
+}
+
+func runConfig(args []string, config Config) int {
+	action := "show"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	switch action {
+	case "show":
+		return runConfigShow(config)
+	case "validate":
+		return runConfigValidate()
+	case "init":
+		return runConfigInit()
+	case "profiles":
+		return runConfigProfiles(config)
+	case "themes":
+		return runConfigThemes(config)
+	}
+	fmt.Fprintln(os.Stderr, msg("err.unknown_command", "config "+action))
+	fmt.Fprintln(os.Stderr, msg("err.help_hint", "config"))
+	return exitUsage
+}
+
+// show は重ね合わせた後の値と、その値を決めた設定元を表示する
+func runConfigShow(config Config) int {
+	cfg, sources, err := loadConfig(*configFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.config", err))
+	}
+	b, _ := json.Marshal(cfg)
+	var values map[string]json.RawMessage
+	_ = json.Unmarshal(b, &values)
+	var kvs []KV
+	for _, f := range configFields() {
+		kvs = append(kvs, KV{Key: f.key, Val: string(values[f.key]) + "\n← " + sources[f.key]})
+	}
+	output(renderTable(msg("config.title"), kvs, tableWidth(), config.Color), "")
+	if err != nil {
+		return exitUsage
+	}
+	return exitOK
+}
+
+// validate は各設定ファイルと環境変数を読み込み、値も含めて検査する
+func runConfigValidate() int {
+	failed := false
+	cfg := defaultConfig()
+	sources := configSources{}
+	for _, layer := range configLayers(*configFlag) {
+		found, err := overlayConfigFile(&cfg, sources, layer)
+		switch {
+		case err != nil:
+			failed = true
+			fmt.Println(msg("config.invalid", err))
+		case found:
+			fmt.Println(msg("config.ok", layer.name, layer.path))
+		default:
+			fmt.Println(msg("config.missing", layer.name, layer.path))
+		}
+	}
+	if err := overlayConfigEnv(&cfg, sources); err != nil {
+		failed = true
+		fmt.Println(msg("config.invalid", err))
+	}
+	for _, err := range validateConfig(cfg) {
+		failed = true
+		fmt.Println(msg("config.invalid", err))
+	}
+	if failed {
+		return exitUsage
+	}
+	fmt.Println(msg("config.valid"))
+	return exitOK
+}
+
+// profiles は定義されたプロファイルと、それぞれが上書きするキーを表示する
+func runConfigProfiles(config Config) int {
+	if len(config.Profiles) == 0 {
+		fmt.Println(msg("config.no_profiles"))
+		return exitOK
+	}
+	var kvs []KV
+	for _, name := range sortedKeys(config.Profiles) {
+		var values map[string]json.RawMessage
+		_ = json.Unmarshal(config.Profiles[name], &values)
+		var lines []string
+		for _, key := range sortedKeys(values) {
+			if !strings.HasPrefix(key, "_") {
+				lines = append(lines, key+": "+string(values[key]))
+			}
+		}
+		key := name
+		if name == config.Profile {
+			key += " " + msg("config.active")
+		}
+		kvs = append(kvs, KV{Key: key, Val: strings.Join(lines, "\n")})
+	}
+	output(renderTable(msg("config.profiles_title"), kvs, tableWidth(), config.Color), "")
+	return exitOK
+}
+
+// themes は組み込みと config.json の themes のテーマを、役割ごとの色見本を付けて表示する
+func runConfigThemes(config Config) int {
+	var lines []string
+	for _, name := range themeNames(config.Themes) {
+		title := name
+		if name == config.Theme {
+			title += " " + msg("config.active")
+		}
+		lines = append(lines, colorize(title, "title", config.Color))
+		theme, err := loadTheme(name, config.Themes, colorLevel)
+		if err != nil {
+			lines = append(lines, "  "+msg("config.invalid", err))
+			continue
+		}
+		// 主な役割とそれ以外で2行に分ける
+		for _, roles := range [][]string{themeRoles[:7], themeRoles[7:]} {
+			var samples []string
+			for _, role := range roles {
+				if config.Color {
+					samples = append(samples, paint(role, theme[role]))
+				} else {
+					samples = append(samples, role)
+				}
+			}
+			lines = append(lines, "  "+strings.Join(samples, "  "))
+		}
+	}
+	output(lines, "")
+	return exitOK
+}
+
+// init は -config（省略時はユーザー設定ディレクトリ）に雛形を書き出す
+func runConfigInit() int {
+	path := *configFlag
+	if path == "" {
+		p, err := userConfigPath()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.config", err))
+			return exitError
+		}
+		path = p
+	}
+	if _, err := os.Stat(path); err == nil && !*forceFlag {
+		fmt.Fprintln(os.Stderr, msg("err.config_exists", path))
+		return exitError
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.config", err))
+		return exitError
+	}
+	if err := os.WriteFile(path, []byte(configTemplate), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.config", err))
+		return exitError
+	}
+	fmt.Println(msg("config.created", path))
+	return exitOK
+}
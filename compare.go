@@ -0,0 +1,220 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"strings"
+)
+
+// 比較表の1列に必要な最小幅。これを下回る場合は縦積み表示にする
+const minCompareColumn = 18
+
+type compareRow struct {
+	Label  string
+	Values []string
+}
+
+func registrantOrg(rec *Record) string {
+	for _, c := range rec.Contacts {
+		if c.Role == "registrant" && c.Organization != "" {
+			return c.Organization
+		}
+	}
+	return rec.Organization
+}
+
+func statusCodes(rec *Record) []string {
+	var out []string
+	for _, st := range rec.Status {
+		// "clientTransferProhibited https://icann.org/epp#..." の URL 部分を除く
+		if f := strings.Fields(st); len(f) > 0 && strings.HasPrefix(f[len(f)-1], "http") {
+			st = strings.Join(f[:len(f)-1], " ")
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+func formatDay(rec *Record, which string) string {
+	t := rec.Created
+	if which == "expiry" {
+		t = rec.Expiry
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(dateSettings.loc).Format("2006-01-02")
+}
+
+func compareRows(recs []*Record, errs []error, lang string) []compareRow {
+	rows := []compareRow{
+		{Label: translateLabel("Registrar", lang)},
+		{Label: translateLabel("Creation Date", lang)},
+		{Label: translateLabel("Registry Expiry Date", lang)},
+		{Label: translateLabel("Domain Status", lang)},
+		{Label: translateLabel("Name Server", lang)},
+		{Label: translateLabel("Registrant Organization", lang)},
+	}
+	for i, rec := range recs {
+		if rec == nil {
+			rows[0].Values = append(rows[0].Values, msg("compare.error", errs[i]))
+			for j := 1; j < len(rows); j++ {
+				rows[j].Values = append(rows[j].Values, "")
+			}
+			continue
+		}
+		rows[0].Values = append(rows[0].Values, rec.Registrar)
+		rows[1].Values = append(rows[1].Values, formatDay(rec, "created"))
+		rows[2].Values = append(rows[2].Values, formatDay(rec, "expiry"))
+		rows[3].Values = append(rows[3].Values, strings.Join(statusCodes(rec), "\n"))
+		rows[4].Values = append(rows[4].Values, displayIDN(strings.Join(rec.NameServers, "\n")))
+		rows[5].Values = append(rows[5].Values, registrantOrg(rec))
+	}
+	return rows
+}
+
+func rowDiffers(r compareRow) bool {
+	for _, v := range r.Values[1:] {
+		if !strings.EqualFold(v, r.Values[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// 先頭（基準）ドメインと値が異なるセルを強調する
+func cellDiffs(r compareRow) []bool {
+	diff := make([]bool, len(r.Values))
+	for i := 1; i < len(r.Values); i++ {
+		diff[i] = !strings.EqualFold(r.Values[i], r.Values[0])
+	}
+	return diff
+}
+
+func runCompare(inputs []string, config Config) []string {
+	names := make([]string, len(inputs))
+	recs := make([]*Record, len(inputs))
+	errs := make([]error, len(inputs))
+	opts := flagLookupOptions(config)
+	for i, in := range inputs {
+		names[i], errs[i] = normalizeDomain(in)
+		if errs[i] != nil {
+			names[i] = in
+			continue
+		}
+		recs[i], errs[i] = lookup(names[i], opts)
+	}
+	return renderCompare(names, compareRows(recs, errs, config.Lang), tableWidth(), config.Color)
+}
+
+func renderCompare(names []string, rows []compareRow, width int, color bool) []string {
+	keyW := dispWidth(msg("compare.item"))
+	for _, r := range rows {
+		if w := dispWidth(r.Label) + 2; w > keyW {
+			keyW = w
+		}
+	}
+	b := tableBorder
+	// 列の間は " ┃ "（compact は2桁の空白）
+	colSep := b.pad + b.v + b.pad
+	if b.v == "" {
+		colSep = b.sep
+	}
+	edges := dispWidth(b.left()) + dispWidth(b.right())
+	n := len(names)
+	colW := (width - edges - keyW - n*dispWidth(colSep)) / n
+	if colW < minCompareColumn {
+		return renderCompareStacked(names, rows, width, color)
+	}
+
+	line := func(l, m, r string) []string {
+		if !b.rules {
+			return nil
+		}
+		padW := dispWidth(b.pad)
+		s := l + b.fill(keyW+2*padW)
+		for i := 0; i < n; i++ {
+			s += m + b.fill(colW+2*padW)
+		}
+		return []string{colorize(s+r, "title", color)}
+	}
+	// 各セルを折り返し、行の高さを揃えて描画する
+	rowLines := func(label string, cells []string, diff []bool) []string {
+		labelCol := wrapByWidth(label, keyW)
+		cols := make([][]string, n)
+		height := len(labelCol)
+		for i, c := range cells {
+			if c == "" {
+				c = "-"
+			}
+			for _, part := range strings.Split(c, "\n") {
+				cols[i] = append(cols[i], wrapByWidth(part, colW)...)
+			}
+			if len(cols[i]) > height {
+				height = len(cols[i])
+			}
+		}
+		var out []string
+		for h := 0; h < height; h++ {
+			cell := func(parts []string, w int) string {
+				if h < len(parts) {
+					return padRightByWidth(parts[h], w)
+				}
+				return strings.Repeat(" ", w)
+			}
+			s := colorize(b.left(), "title", color) + colorize(cell(labelCol, keyW), "label", color)
+			for i := 0; i < n; i++ {
+				role := "value"
+				if diff != nil && diff[i] {
+					role = "warning"
+				}
+				s += colorize(colSep, "title", color) + colorize(cell(cols[i], colW), role, color)
+			}
+			out = append(out, s+colorize(b.right(), "title", color))
+		}
+		return out
+	}
+
+	out := line(b.tl, b.tt, b.tr)
+	heads := make([]string, n)
+	for i, name := range names {
+		heads[i] = displayIDN(name)
+	}
+	out = append(out, rowLines(msg("compare.item"), heads, nil)...)
+	out = append(out, line(b.lt, b.cross, b.rt)...)
+	for i, r := range rows {
+		label := r.Label
+		if rowDiffers(r) {
+			label += " *"
+		}
+		out = append(out, rowLines(label, r.Values, cellDiffs(r))...)
+		if i < len(rows)-1 {
+			out = append(out, line(b.lt, b.cross, b.rt)...)
+		}
+	}
+	out = append(out, line(b.bl, b.bt, b.br)...)
+	return out
+}
+
+// 端末幅が足りない場合はドメインごとのセクションを縦に並べる
+func renderCompareStacked(names []string, rows []compareRow, width int, color bool) []string {
+	var sections []Section
+	for i, name := range names {
+		s := Section{ID: name, Title: displayIDN(name)}
+		for _, r := range rows {
+			label := r.Label
+			if rowDiffers(r) {
+				label += " *"
+			}
+			v := r.Values[i]
+			if v == "" {
+				v = "-"
+			}
+			s.Rows = append(s.Rows, KV{Key: label, Val: v})
+		}
+		sections = append(sections, s)
+	}
+	return renderSectionTable(msg("compare.title"), sections, width, color)
+}
@@ -0,0 +1,341 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// ファイル名を補完するフラグ
+var fileFlags = map[string]bool{"f": true, "o": true, "html": true, "template": true, "config": true}
+
+// flagValues はフラグごとの補完候補（列挙値や入力例）を返す
+func flagValues() map[string][]string {
+	var jprsTypes []string
+	for t := range jprsQueryTypes {
+		jprsTypes = append(jprsTypes, t)
+	}
+	sort.Strings(jprsTypes)
+	var profileNames []string
+	themes := themeNames(nil)
+	if cfg, _, err := loadConfig(*configFlag); err == nil {
+		profileNames = sortedKeys(cfg.Profiles)
+		themes = themeNames(cfg.Themes)
+	}
+	return map[string][]string{
+		"output":          outputFormats,
+		"lang":            availableLangs(),
+		"jprs-type":       jprsTypes,
+		"fields":          exportFieldNames,
+		"field":           exportFieldNames,
+		"server":          serverHosts(),
+		"timeout":         {"5s", "10s", "30s", "1m"},
+		"cache-ttl":       {"0", "15m", "1h", "24h"},
+		"tz":              {"Local", "UTC", "JST", "Asia/Tokyo", "America/New_York", "Europe/London"},
+		"width":           {"80", "100", "120", "160"},
+		"profile":         profileNames,
+		"theme":           themes,
+		"border":          borderNames,
+		"ambiguous-width": {"auto", "narrow", "wide"},
+		"kinds":           squatKinds,
+		"tlds":            append([]string{"all"}, parseTLDList("")...),
+	}
+}
+
+// サブコマンドの位置引数の候補
+func commandArgs() map[string][]string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return map[string][]string{
+		"cache":      {"list", "clear", "prune", "path"},
+		"config":     {"show", "validate", "init", "profiles", "themes"},
+		"help":       names,
+		"completion": completionShells,
+	}
+}
+
+// serverHosts は組み込みの WHOIS サーバ表からホスト名を重複なく返す
+func serverHosts() []string {
+	seen := map[string]bool{}
+	var hosts []string
+	add := func(server string) {
+		host := strings.TrimSuffix(server, ":43")
+		if host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	for _, s := range whoisServers {
+		add(s.server)
+	}
+	add(defaultWhoisServer)
+	for _, key := range sortedKeys(serverOverrides) {
+		add(serverOverrides[key].Host)
+	}
+	return hosts
+}
+
+type completionFlag struct {
+	name, desc string
+	takesValue bool
+}
+
+func commandFlags(c *command) []completionFlag {
+	var out []completionFlag
+	c.flagSet().VisitAll(func(f *flag.Flag) {
+		desc := f.Usage
+		if id := "opt." + strings.ReplaceAll(f.Name, "-", "_"); hasMsg(id) {
+			desc = msg(id)
+		}
+		bf, isBool := f.Value.(interface{ IsBoolFlag() bool })
+		out = append(out, completionFlag{name: f.Name, desc: desc, takesValue: !(isBool && bf.IsBoolFlag())})
+	})
+	return out
+}
+
+func runCompletion(args []string, config Config) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, msg("err.completion_usage"))
+		return exitUsage
+	}
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion()
+	case "zsh":
+		script = zshCompletion()
+	case "fish":
+		script = fishCompletion()
+	case "powershell", "pwsh":
+		script = powershellCompletion()
+	default:
+		fmt.Fprintln(os.Stderr, msg("err.completion_usage"))
+		return exitUsage
+	}
+	fmt.Print(script)
+	return exitOK
+}
+
+func commandNames() []string {
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func bashCompletion() string {
+	var b strings.Builder
+	b.WriteString("# bash completion for whois\n# source <(whois completion bash)\n\n_whois() {\n")
+	b.WriteString("    local cur prev cmd\n    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n    cmd=lookup\n")
+	fmt.Fprintf(&b, "    if [[ ${COMP_CWORD} -gt 1 ]]; then\n        case \"${COMP_WORDS[1]}\" in\n            %s) cmd=\"${COMP_WORDS[1]}\" ;;\n        esac\n    fi\n\n", strings.Join(commandNames(), "|"))
+
+	b.WriteString("    case \"$prev\" in\n")
+	var files []string
+	for f := range fileFlags {
+		files = append(files, "-"+f)
+	}
+	sort.Strings(files)
+	fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", strings.Join(files, "|"))
+	values := flagValues()
+	for _, name := range sortedKeys(values) {
+		if name == "fields" {
+			// カンマ区切りの最後の要素だけを補完する
+			fmt.Fprintf(&b, "        -fields)\n            local prefix=\"\"\n            [[ \"$cur\" == *,* ]] && prefix=\"${cur%%,*},\"\n            compopt -o nospace 2>/dev/null\n            COMPREPLY=($(compgen -P \"$prefix\" -W %q -- \"${cur##*,}\")); return ;;\n", strings.Join(values[name], " "))
+			continue
+		}
+		fmt.Fprintf(&b, "        -%s)\n            COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(values[name], " "))
+	}
+	b.WriteString("        -date-format)\n            return ;;\n    esac\n\n")
+
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n        case \"$cmd\" in\n")
+	for _, c := range commands {
+		var names []string
+		for _, f := range commandFlags(c) {
+			names = append(names, "-"+f.name)
+		}
+		names = append(names, "-help")
+		fmt.Fprintf(&b, "            %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", c.name, strings.Join(names, " "))
+	}
+	b.WriteString("        esac\n        return\n    fi\n\n")
+
+	fmt.Fprintf(&b, "    if [[ ${COMP_CWORD} -eq 1 ]]; then\n        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n        return\n    fi\n", strings.Join(commandNames(), " "))
+	b.WriteString("    case \"$cmd\" in\n")
+	cargs := commandArgs()
+	for _, name := range sortedKeys(cargs) {
+		fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", name, strings.Join(cargs[name], " "))
+	}
+	b.WriteString("    esac\n}\n\ncomplete -F _whois whois\n")
+	return b.String()
+}
+
+var zshEscaper = strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:")
+
+func zshCompletion() string {
+	var b strings.Builder
+	b.WriteString("#compdef whois\n# whois completion zsh > \"${fpath[1]}/_whois\"\n\n_whois() {\n    local cmd=lookup\n    local -a commands\n    commands=(\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "        '%s:%s'\n", c.name, zshEscaper.Replace(msg("cmd."+c.name)))
+	}
+	b.WriteString("    )\n    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then\n        _describe -t commands 'whois command' commands\n    fi\n")
+	fmt.Fprintf(&b, "    case $words[2] in\n        %s)\n            cmd=$words[2]\n            shift words\n            (( CURRENT-- ))\n            ;;\n    esac\n\n    case $cmd in\n", strings.Join(commandNames(), "|"))
+
+	values := flagValues()
+	cargs := commandArgs()
+	for _, c := range commands {
+		fmt.Fprintf(&b, "        %s)\n            _arguments -s \\\n", c.name)
+		for _, f := range commandFlags(c) {
+			spec := fmt.Sprintf("-%s[%s]", f.name, zshEscaper.Replace(f.desc))
+			if f.takesValue {
+				switch {
+				case fileFlags[f.name]:
+					spec += ":file:_files"
+				case len(values[f.name]) > 0:
+					spec += fmt.Sprintf(":%s:(%s)", f.name, strings.Join(values[f.name], " "))
+				default:
+					spec += ":" + f.name + ":"
+				}
+			}
+			fmt.Fprintf(&b, "                '%s' \\\n", spec)
+		}
+		if a, ok := cargs[c.name]; ok {
+			fmt.Fprintf(&b, "                '1:argument:(%s)'\n", strings.Join(a, " "))
+		} else {
+			b.WriteString("                '*:domain:'\n")
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString("    esac\n}\n\n_whois \"$@\"\n")
+	return b.String()
+}
+
+var fishEscaper = strings.NewReplacer("\\", "\\\\", "'", "\\'")
+
+func fishCompletion() string {
+	var b strings.Builder
+	// lookup のフラグは他のサブコマンドが入力されていないときに候補にする
+	var others []string
+	for _, name := range commandNames() {
+		if name != defaultCommand {
+			others = append(others, name)
+		}
+	}
+	names := strings.Join(others, " ")
+	b.WriteString("# fish completion for whois\n# whois completion fish > ~/.config/fish/completions/whois.fish\n\ncomplete -c whois -f\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "complete -c whois -n __fish_use_subcommand -a %s -d '%s'\n", c.name, fishEscaper.Replace(msg("cmd."+c.name)))
+	}
+	values := flagValues()
+	cargs := commandArgs()
+	for _, c := range commands {
+		cond := fmt.Sprintf("__fish_seen_subcommand_from %s", c.name)
+		if c.name == defaultCommand {
+			cond = fmt.Sprintf("not __fish_seen_subcommand_from %s", names)
+		}
+		for _, f := range commandFlags(c) {
+			line := fmt.Sprintf("complete -c whois -n '%s' -o %s", cond, f.name)
+			if f.takesValue {
+				switch {
+				case fileFlags[f.name]:
+					line += " -r -F"
+				case len(values[f.name]) > 0:
+					line += fmt.Sprintf(" -x -a '%s'", strings.Join(values[f.name], " "))
+				default:
+					line += " -x"
+				}
+			}
+			fmt.Fprintf(&b, "%s -d '%s'\n", line, fishEscaper.Replace(f.desc))
+		}
+		if a, ok := cargs[c.name]; ok {
+			fmt.Fprintf(&b, "complete -c whois -n '%s' -a '%s'\n", cond, strings.Join(a, " "))
+		}
+	}
+	return b.String()
+}
+
+func psList(xs []string) string {
+	quoted := make([]string, len(xs))
+	for i, x := range xs {
+		quoted[i] = "'" + strings.ReplaceAll(x, "'", "''") + "'"
+	}
+	return "@(" + strings.Join(quoted, ", ") + ")"
+}
+
+func powershellCompletion() string {
+	var b strings.Builder
+	b.WriteString("# PowerShell completion for whois\n# whois completion powershell | Out-String | Invoke-Expression\n\n")
+	b.WriteString("Register-ArgumentCompleter -Native -CommandName whois, whois.exe -ScriptBlock {\n    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	fmt.Fprintf(&b, "    $commands = %s\n", psList(commandNames()))
+	b.WriteString("    $flags = @{\n")
+	for _, c := range commands {
+		var names []string
+		for _, f := range commandFlags(c) {
+			names = append(names, "-"+f.name)
+		}
+		names = append(names, "-help")
+		fmt.Fprintf(&b, "        '%s' = %s\n", c.name, psList(names))
+	}
+	b.WriteString("    }\n    $values = @{\n")
+	values := flagValues()
+	for _, name := range sortedKeys(values) {
+		fmt.Fprintf(&b, "        '-%s' = %s\n", name, psList(values[name]))
+	}
+	b.WriteString("    }\n    $arguments = @{\n")
+	cargs := commandArgs()
+	for _, name := range sortedKeys(cargs) {
+		fmt.Fprintf(&b, "        '%s' = %s\n", name, psList(cargs[name]))
+	}
+	var files []string
+	for f := range fileFlags {
+		files = append(files, "-"+f)
+	}
+	sort.Strings(files)
+	fmt.Fprintf(&b, "    }\n    $fileFlags = %s\n\n", psList(files))
+	b.WriteString(`    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and $words.Count -gt 0) {
+        $words = @($words | Select-Object -First ($words.Count - 1))
+    }
+    $cmd = 'lookup'
+    if ($words.Count -gt 0 -and $commands -contains $words[0]) {
+        $cmd = $words[0]
+    }
+    $prev = if ($words.Count -gt 0) { $words[-1] } else { '' }
+
+    $candidates = @()
+    if ($fileFlags -contains $prev) {
+        return
+    } elseif ($values.ContainsKey($prev)) {
+        $candidates = $values[$prev]
+    } elseif ($wordToComplete -like '-*') {
+        $candidates = $flags[$cmd]
+    } elseif ($words.Count -eq 0) {
+        $candidates = $commands
+    } elseif ($arguments.ContainsKey($cmd)) {
+        $candidates = $arguments[$cmd]
+    }
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`)
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,402 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Lang           string                       `json:"lang"`
+	DefaultOutput  string                       `json:"default_output"`
+	Color          bool                         `json:"color"`
+	Theme          string                       `json:"theme"`
+	Themes         map[string]map[string]string `json:"themes"`
+	Border         string                       `json:"border"`
+	AmbiguousWidth string                       `json:"ambiguous_width"`
+	IDNDisplay     string                       `json:"idn_display"`
+	LocalesDir     string                       `json:"locales_dir"`
+	Templates      map[string]string            `json:"templates"`
+	Timezone       string                       `json:"timezone"`
+	DateFormat     string                       `json:"date_format"`
+	RelativeDates  bool                         `json:"relative_dates"`
+	CacheTTL       string                       `json:"cache_ttl"`
+	Timeout        string                       `json:"timeout"`
+	RateLimit      string                       `json:"rate_limit"`
+	Servers        map[string]ServerConfig      `json:"servers"`
+	Profile        string                       `json:"profile"`
+	Profiles       map[string]json.RawMessage   `json:"profiles"`
+}
+
+// プロジェクト（カレントディレクトリ）の設定ファイル
+const configFile = "config.json"
+
+func defaultConfig() Config {
+	return Config{DefaultOutput: "conventional", Color: true, Theme: defaultTheme, Border: "heavy", AmbiguousWidth: "auto", IDNDisplay: "both", Timeout: "8s"}
+}
+
+// configField は Config の JSON キーと型
+type configField struct {
+	key  string
+	kind reflect.Kind
+}
+
+func configFields() []configField {
+	t := reflect.TypeOf(Config{})
+	fields := make([]configField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		fields = append(fields, configField{key: key, kind: t.Field(i).Type.Kind()})
+	}
+	return fields
+}
+
+// 設定の出どころ（キー → "user (~/.config/whois/config.json)" など）
+type configSources map[string]string
+
+type configLayer struct {
+	name     string
+	path     string
+	required bool
+}
+
+func systemConfigPath() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("ProgramData"), "whois", "config.json")
+	}
+	return "/etc/whois/config.json"
+}
+
+func userConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "whois", "config.json"), nil
+}
+
+// configLayers は後に並ぶものほど優先される設定ファイルの一覧を返す。
+// -config を指定した場合はカレントディレクトリの config.json の代わりに読み込む
+func configLayers(explicit string) []configLayer {
+	layers := []configLayer{{name: "system", path: systemConfigPath()}}
+	if home, err := os.UserHomeDir(); err == nil {
+		layers = append(layers, configLayer{name: "user", path: filepath.Join(home, ".whois.json")})
+	}
+	if path, err := userConfigPath(); err == nil {
+		layers = append(layers, configLayer{name: "user", path: path})
+	}
+	if explicit != "" {
+		layers = append(layers, configLayer{name: "config", path: explicit, required: true})
+	} else {
+		layers = append(layers, configLayer{name: "project", path: configFile})
+	}
+	return layers
+}
+
+// overlayConfigFile は1つの設定ファイルに書かれたキーだけを cfg に上書きする。
+// ファイルがなければ false を返す（required の場合はエラー）
+func overlayConfigFile(cfg *Config, sources configSources, layer configLayer) (bool, error) {
+	data, err := os.ReadFile(layer.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !layer.required {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, overlayConfigJSON(cfg, sources, data, layer.path, fmt.Sprintf("%s (%s)", layer.name, layer.path), nil)
+}
+
+// overlayConfigJSON は data に書かれたキーだけを cfg に上書きし、source を記録する。
+// where はエラー表示用の場所、deny はその場所で書けないキー
+func overlayConfigJSON(cfg *Config, sources configSources, data []byte, where, source string, deny map[string]bool) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("%s: %w", where, describeJSONError(data, err))
+	}
+	known := map[string]bool{}
+	for _, f := range configFields() {
+		known[f.key] = !deny[f.key]
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		// "_eg" のような "_" で始まるキーはコメントとして読み飛ばす
+		if !known[k] && !strings.HasPrefix(k, "_") {
+			return errors.New(msg("err.unknown_key", where, k))
+		}
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%s: %w", where, describeJSONError(data, err))
+	}
+	for _, k := range names {
+		if known[k] {
+			sources[k] = source
+		}
+	}
+	return nil
+}
+
+// プロファイルの中では別のプロファイルを選んだり定義したりできない
+var profileDeny = map[string]bool{"profile": true, "profiles": true}
+
+// selectedProfile は -profile > WHOIS_PROFILE > 設定ファイルの profile の順で使うプロファイル名を返す
+func selectedProfile(cfg Config) string {
+	if flagWasSet("profile") {
+		return *profileFlag
+	}
+	if v, ok := os.LookupEnv(configEnvName("profile")); ok {
+		return v
+	}
+	return cfg.Profile
+}
+
+// overlayConfigProfile は profiles[name] に書かれたキーを cfg に上書きする
+func overlayConfigProfile(cfg *Config, sources configSources, name string) error {
+	if name == "" {
+		return nil
+	}
+	data, ok := cfg.Profiles[name]
+	if !ok {
+		return errors.New(msg("err.unknown_profile", name, strings.Join(sortedKeys(cfg.Profiles), ", ")))
+	}
+	return overlayConfigJSON(cfg, sources, data, "profiles."+name, "profile "+name, profileDeny)
+}
+
+func describeJSONError(data []byte, err error) error {
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		line := bytes.Count(data[:se.Offset], []byte("\n")) + 1
+		col := int(se.Offset) - bytes.LastIndexByte(data[:se.Offset], '\n')
+		return errors.New(msg("err.json_syntax", line, col, err))
+	}
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) {
+		return errors.New(msg("err.json_type", te.Field, te.Type, te.Value))
+	}
+	return err
+}
+
+// WHOIS_LANG, WHOIS_DEFAULT_OUTPUT, WHOIS_COLOR ... の環境変数で上書きする
+func configEnvName(key string) string {
+	return "WHOIS_" + strings.ToUpper(key)
+}
+
+func overlayConfigEnv(cfg *Config, sources configSources) error {
+	for _, f := range configFields() {
+		name := configEnvName(f.key)
+		v, ok := os.LookupEnv(name)
+		if !ok || f.kind == reflect.Map {
+			continue
+		}
+		var raw []byte
+		if f.kind == reflect.Bool {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.New(msg("err.env_bool", name, v))
+			}
+			raw, _ = json.Marshal(map[string]bool{f.key: b})
+		} else {
+			raw, _ = json.Marshal(map[string]string{f.key: v})
+		}
+		if err := json.Unmarshal(raw, cfg); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		sources[f.key] = "env " + name
+	}
+	return nil
+}
+
+// overlayConfigFlags はコマンドラインで明示したフラグを最後に反映する
+func overlayConfigFlags(cfg *Config, sources configSources) {
+	set := func(key, name string, apply func()) {
+		if flagWasSet(name) {
+			apply()
+			sources[key] = "flag -" + name
+		}
+	}
+	set("lang", "lang", func() { cfg.Lang = *langFlag })
+	set("timezone", "tz", func() { cfg.Timezone = *tzFlag })
+	set("date_format", "date-format", func() { cfg.DateFormat = *dateFormatFlag })
+	set("relative_dates", "relative", func() { cfg.RelativeDates = *relativeFlag })
+	set("cache_ttl", "cache-ttl", func() { cfg.CacheTTL = *cacheTTLFlag })
+	set("timeout", "timeout", func() { cfg.Timeout = timeoutFlag.String() })
+	set("profile", "profile", func() { cfg.Profile = *profileFlag })
+	set("color", "nocolor", func() { cfg.Color = !*noColorFlag })
+	set("theme", "theme", func() { cfg.Theme = *themeFlag })
+	set("border", "border", func() { cfg.Border = *borderFlag })
+	set("ambiguous_width", "ambiguous-width", func() { cfg.AmbiguousWidth = *ambiguousFlag })
+	set("idn_display", "unicode", func() { cfg.IDNDisplay = "unicode" })
+	set("idn_display", "punycode", func() { cfg.IDNDisplay = "punycode" })
+}
+
+// loadConfig は 既定値 < system < user < project（または -config）< プロファイル < 環境変数 < フラグ の順に重ねる。
+// エラーがあっても、それまでに読み込んだ設定は返す
+func loadConfig(explicit string) (Config, configSources, error) {
+	cfg := defaultConfig()
+	sources := configSources{}
+	for _, f := range configFields() {
+		sources[f.key] = "default"
+	}
+	for _, layer := range configLayers(explicit) {
+		if _, err := overlayConfigFile(&cfg, sources, layer); err != nil {
+			return cfg, sources, err
+		}
+	}
+	if err := overlayConfigProfile(&cfg, sources, selectedProfile(cfg)); err != nil {
+		return cfg, sources, err
+	}
+	if err := overlayConfigEnv(&cfg, sources); err != nil {
+		return cfg, sources, err
+	}
+	overlayConfigFlags(&cfg, sources)
+	return cfg, sources, nil
+}
+
+// validateConfig は値として解釈できない設定を返す
+func validateConfig(cfg Config) []error {
+	var errs []error
+	valid := false
+	for _, f := range outputFormats {
+		if strings.EqualFold(cfg.DefaultOutput, f) {
+			valid = true
+		}
+	}
+	if !valid {
+		errs = append(errs, errors.New("default_output: "+msg("err.unknown_output", cfg.DefaultOutput)))
+	}
+	switch strings.ToLower(cfg.Timezone) {
+	case "", "local", "utc", "jst":
+	default:
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("timezone: %v", err))
+		}
+	}
+	for key, val := range map[string]string{"cache_ttl": cfg.CacheTTL, "timeout": cfg.Timeout, "rate_limit": cfg.RateLimit} {
+		if val != "" {
+			if _, err := time.ParseDuration(val); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", key, err))
+			}
+		}
+	}
+	if _, ok := cfg.Themes[cfg.Theme]; !ok && cfg.Theme != "" && builtinThemes[cfg.Theme] == nil {
+		errs = append(errs, errors.New("theme: "+msg("err.unknown_theme", cfg.Theme, strings.Join(themeNames(cfg.Themes), ", "))))
+	}
+	for _, name := range sortedKeys(cfg.Themes) {
+		if _, err := loadTheme(name, cfg.Themes, colorTrueColor); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if b := strings.ToLower(cfg.Border); b != "" && !slices.Contains(borderNames, b) {
+		errs = append(errs, errors.New("border: "+msg("err.unknown_border", cfg.Border, strings.Join(borderNames, ", "))))
+	}
+	if m := strings.ToLower(cfg.IDNDisplay); m != "" && !slices.Contains(idnDisplayModes, m) {
+		errs = append(errs, errors.New(msg("err.idn_display", cfg.IDNDisplay)))
+	}
+	switch strings.ToLower(cfg.AmbiguousWidth) {
+	case "", "auto", "narrow", "wide":
+	default:
+		errs = append(errs, errors.New("ambiguous_width: "+msg("err.unknown_ambiguous_width", cfg.AmbiguousWidth)))
+	}
+	if cfg.LocalesDir != "" {
+		if fi, err := os.Stat(cfg.LocalesDir); err != nil || !fi.IsDir() {
+			errs = append(errs, errors.New("locales_dir: "+msg("err.not_directory", cfg.LocalesDir)))
+		}
+	}
+	for key, sc := range cfg.Servers {
+		if err := validateServerConfig(key, sc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for name, text := range cfg.Templates {
+		if _, err := loadTemplate(text, "", cfg); err != nil {
+			errs = append(errs, fmt.Errorf("templates.%s: %v", name, err))
+		}
+	}
+	if cfg.Profile != "" {
+		if _, ok := cfg.Profiles[cfg.Profile]; !ok {
+			errs = append(errs, errors.New("profile: "+msg("err.unknown_profile", cfg.Profile, strings.Join(sortedKeys(cfg.Profiles), ", "))))
+		}
+	}
+	// 各プロファイルは既定値と独自テーマに重ねて、書かれた値だけを検査する（独自テーマの誤りは報告済みなので除く）
+	reported := map[string]bool{}
+	for _, err := range errs {
+		reported[err.Error()] = true
+	}
+	for _, name := range sortedKeys(cfg.Profiles) {
+		p := defaultConfig()
+		p.Profiles, p.Themes = cfg.Profiles, maps.Clone(cfg.Themes)
+		if err := overlayConfigProfile(&p, configSources{}, name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.Profile, p.Profiles = "", nil
+		for _, err := range validateConfig(p) {
+			if !reported[err.Error()] {
+				errs = append(errs, fmt.Errorf("profiles.%s.%v", name, err))
+			}
+		}
+	}
+	return errs
+}
+
+// whois config init で書き出す雛形
+const configTemplate = `{
+  "lang": "",
+  "default_output": "conventional",
+  "color": true,
+  "theme": "dark",
+  "border": "heavy",
+  "ambiguous_width": "auto",
+  "idn_display": "both",
+  "timezone": "Local",
+  "date_format": "2006-01-02 15:04:05 MST",
+  "relative_dates": false,
+  "cache_ttl": "",
+  "timeout": "8s",
+  "rate_limit": "",
+  "profile": "",
+  "templates": {
+    "summary": "{{.Domain}} {{.Registrar}} {{.Expiry.Format \"2006-01-02\"}} ({{daysUntil .Expiry}} days)"
+  },
+
+  "_eg": {
+    "lang": "ja/en/any installed locale (empty: $LC_ALL/$LANG)",
+    "default_output": "table/conventional/raw/json/csv/tsv/ndjson/yaml/markdown",
+    "color": "bool",
+    "theme": "dark/light/high-contrast/monochrome or a name from themes",
+    "border": "heavy/light/rounded/double/ascii/none/compact (compact: no frame, aligned columns only)",
+    "ambiguous_width": "auto/narrow/wide, display width of East Asian ambiguous characters such as box drawing (auto: $RUNEWIDTH_EASTASIAN or a CJK locale)",
+    "idn_display": "both/unicode/punycode, how internationalized domain names are shown in table and conventional output",
+    "themes": "name -> {\"base\": built-in theme, role -> color}; roles: label, value, title, warning, error, status-ok, status-risk, version, copyright, usage, option; color: bold/dim/italic/underline/reverse, red/bright-red/..., 0-255, #rrggbb, bg:<color>",
+    "locales_dir": "directory containing additional <lang>.json catalogs",
+    "templates": "name -> Go text/template, used with -template <name>",
+    "timezone": "Local/UTC/JST/IANA name (e.g. Asia/Tokyo) for displayed dates",
+    "date_format": "Go time layout for displayed dates",
+    "relative_dates": "bool, append 'in 43 days' / '12 years ago' to dates",
+    "cache_ttl": "duration (e.g. 1h) to reuse cached responses, empty disables the cache",
+    "timeout": "network timeout (e.g. 5s, 2m)",
+    "rate_limit": "minimum interval between queries to the same server (e.g. 1s), empty disables it",
+    "servers": "TLD or suffix -> \"host[:port]\" or {\"host\", \"query\" (e.g. \"-T dn,ace %s\"), \"charset\"}",
+    "profile": "profile used when neither -profile nor WHOIS_PROFILE is given",
+    "profiles": "name -> any of the keys above, selected with -profile <name> or WHOIS_PROFILE"
+  }
+}
+`
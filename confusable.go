@@ -0,0 +1,269 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:generate go run gen_confusables.go -o data/confusables.tsv
+
+// Unicode の confusables.txt（UTS #39）から生成した「文字 → 原型」の表
+//
+//go:embed data/confusables.tsv
+var confusablesData string
+
+var (
+	confusablesOnce sync.Once
+	confusablesMap  map[rune]string
+)
+
+// confusablePrototypes は confusables.tsv を初回だけ読み込み、文字 → 原型の表を返す
+func confusablePrototypes() map[rune]string {
+	confusablesOnce.Do(func() {
+		confusablesMap = map[rune]string{}
+		for _, line := range strings.Split(confusablesData, "\n") {
+			from, to, ok := strings.Cut(line, "\t")
+			if !ok || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r, err := strconv.ParseUint(from, 16, 32)
+			if err != nil {
+				continue
+			}
+			var proto []rune
+			for _, h := range strings.Fields(to) {
+				if c, err := strconv.ParseUint(h, 16, 32); err == nil {
+					proto = append(proto, rune(c))
+				}
+			}
+			confusablesMap[rune(r)] = string(proto)
+		}
+	})
+	return confusablesMap
+}
+
+// 1つの名前に混在してよい文字種の組（UTS #39 の Highly Restrictive。Common / Inherited は数えない）
+var allowedScriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// よく使う文字種を先に調べ、それ以外は unicode.Scripts から探す
+var commonScripts = []string{"Latin", "Common", "Inherited", "Han", "Hiragana", "Katakana", "Cyrillic", "Greek", "Armenian", "Hangul"}
+
+func runeScript(r rune) string {
+	for _, name := range commonScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for _, name := range sortedKeys(unicode.Scripts) {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return "Unknown"
+}
+
+// skeleton は UTS #39 の skeleton（NFD → 原型に置換 → NFD）を返す。skeleton が同じ名前は見分けにくい
+func skeleton(s string) string {
+	protos := confusablePrototypes()
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if p, ok := protos[r]; ok {
+			b.WriteString(p)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// confusableChar は名前に含まれるラテン文字以外で、ラテン文字と見分けにくい文字
+type confusableChar struct {
+	r      rune
+	script string
+	latin  string
+}
+
+// lookalike は名前の文字種と見分けにくさの分析結果
+type lookalike struct {
+	unicode   string
+	scripts   []string
+	skeleton  string
+	latin     string // ラテン文字に似た文字だけを置き換えた読み（уаһоо.com → yahoo.com）
+	chars     []confusableChar
+	mixed     bool // 許されない文字種の組み合わせ
+	wholeFake bool // すべての文字がラテン文字に見える別の文字種（уаһоо.com など）
+}
+
+func (l lookalike) suspicious() bool { return l.mixed || l.wholeFake }
+
+// analyzeLookalike は ASCII / punycode の名前を Unicode 表記に戻し、ラベルごとに分析する。
+// 文字種の混在や「すべてラテン文字に見える」の判定はラベル単位（уаһоо.com の .com は数えない）
+func analyzeLookalike(name string) lookalike {
+	u := name
+	if s, ok := unicodeDomain(name); ok {
+		u = s
+	}
+	l := lookalike{unicode: u, skeleton: skeleton(u)}
+	protos := confusablePrototypes()
+	var latin strings.Builder
+	for _, r := range u {
+		if p, ok := latinPrototype(protos, r); ok && r >= utf8.RuneSelf {
+			latin.WriteString(p)
+		} else {
+			latin.WriteRune(r)
+		}
+	}
+	l.latin = latin.String()
+
+	for _, label := range strings.Split(u, ".") {
+		var scripts []string
+		letters, latinLike := 0, 0
+		for _, r := range label {
+			script := runeScript(r)
+			if script != "Common" && script != "Inherited" && !slices.Contains(scripts, script) {
+				scripts = append(scripts, script)
+			}
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			letters++
+			if script == "Latin" {
+				continue
+			}
+			if p, ok := latinPrototype(protos, r); ok {
+				latinLike++
+				if !slices.ContainsFunc(l.chars, func(c confusableChar) bool { return c.r == r }) {
+					l.chars = append(l.chars, confusableChar{r: r, script: script, latin: p})
+				}
+			}
+		}
+		for _, s := range scripts {
+			if !slices.Contains(l.scripts, s) {
+				l.scripts = append(l.scripts, s)
+			}
+		}
+		l.mixed = l.mixed || !allowedScripts(scripts)
+		l.wholeFake = l.wholeFake || (letters > 0 && latinLike == letters)
+	}
+	return l
+}
+
+func allowedScripts(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, set := range allowedScriptSets {
+		ok := true
+		for _, s := range scripts {
+			if !slices.Contains(set, s) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// latinPrototype は r の原型が ASCII ならその小文字を返す（ドメイン名は小文字なので "0" の原型 "O" は "o" と読む）
+func latinPrototype(protos map[rune]string, r rune) (string, bool) {
+	p, ok := protos[r]
+	if !ok || hasNonASCII(p) {
+		return "", false
+	}
+	return strings.ToLower(p), true
+}
+
+// reasons は警告の理由を表示用の文にする
+func (l lookalike) reasons() []string {
+	var out []string
+	if l.mixed {
+		out = append(out, msg("confusable.mixed", strings.Join(l.scripts, ", ")))
+	}
+	if l.wholeFake {
+		out = append(out, msg("confusable.whole", l.latin))
+	} else if len(l.chars) > 0 {
+		var cs []string
+		for _, c := range l.chars {
+			cs = append(cs, fmt.Sprintf("%c→%s", c.r, c.latin))
+		}
+		out = append(out, msg("confusable.chars", strings.Join(cs, " ")))
+	}
+	return out
+}
+
+// lookalikeKVs は検索した名前が紛らわしい場合に表・通常表示の先頭に出す警告の行を返す
+func lookalikeKVs(name string) []KV {
+	l := analyzeLookalike(name)
+	if !l.suspicious() {
+		return nil
+	}
+	var kvs []KV
+	for i, r := range l.reasons() {
+		kv := KV{Val: r, Role: "warning"}
+		if i == 0 {
+			kv.Key = msg("confusable.label")
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
+
+// runConfusables は whois confusables。問い合わせずに名前だけを分析する
+func runConfusables(args []string, config Config) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, msg("err.confusables_usage"))
+		return exitUsage
+	}
+	code := exitOK
+	for _, a := range args {
+		name, err := normalizeDomain(a)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = exitUsage
+			continue
+		}
+		l := analyzeLookalike(name)
+		verdict := msg("confusable.ok")
+		role := "status-ok"
+		if l.suspicious() {
+			verdict = strings.Join(l.reasons(), "\n")
+			role = "status-risk"
+		}
+		kvs := []KV{
+			{Key: msg("confusable.unicode"), Val: l.unicode},
+			{Key: msg("confusable.punycode"), Val: name},
+			{Key: msg("confusable.scripts"), Val: strings.Join(l.scripts, ", ")},
+			{Key: msg("confusable.skeleton"), Val: l.skeleton},
+			{Key: msg("confusable.verdict"), Val: verdict, Role: role},
+		}
+		for i, c := range l.chars {
+			key := ""
+			if i == 0 {
+				key = msg("confusable.latin")
+			}
+			kvs = append(kvs, KV{Key: key, Val: fmt.Sprintf("%c  U+%04X  %s → %s", c.r, c.r, c.script, c.latin), Role: "warning"})
+		}
+		output(renderTable(l.unicode, kvs, tableWidth(), config.Color), "")
+	}
+	return code
+}
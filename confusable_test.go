@@ -0,0 +1,72 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import "testing"
+
+func TestConfusablePrototypes(t *testing.T) {
+	protos := confusablePrototypes()
+	// confusables.txt は 6000 件を超える（抜粋ではないこと）
+	if len(protos) < 6000 {
+		t.Errorf("confusablePrototypes has %d entries, want the whole confusables.txt", len(protos))
+	}
+	tests := []struct {
+		r    rune
+		want string
+	}{
+		{'m', "rn"}, // 複数の文字の原型
+		{'0', "O"},
+		{'1', "l"},
+		{'а', "a"}, // キリル文字
+		{'ο', "o"}, // ギリシャ文字
+		{'ｍ', ""},  // NFD で変わらない全角は表にない（IDNA の対応づけで ASCII になる）
+	}
+	for _, tt := range tests {
+		if got := protos[tt.r]; got != tt.want {
+			t.Errorf("confusablePrototypes[%q] = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"уаһоо.com", "yahoo.com", true},
+		{"modern.com", "rnodern.com", true},
+		{"paypal.com", "pаypаl.com", true},
+		{"exаmple.com", "example.com", true},
+		{"\u00e9", "e\u0301", true}, // 合成済みと結合文字
+		{"example.com", "exanple.com", false},
+	}
+	for _, tt := range tests {
+		if got := skeleton(tt.a) == skeleton(tt.b); got != tt.same {
+			t.Errorf("skeleton(%q) = %q, skeleton(%q) = %q, want same = %v", tt.a, skeleton(tt.a), tt.b, skeleton(tt.b), tt.same)
+		}
+	}
+}
+
+func TestAnalyzeLookalike(t *testing.T) {
+	tests := []struct {
+		name      string
+		mixed     bool
+		wholeFake bool
+		latin     string
+	}{
+		{"example.com", false, false, "example.com"},
+		{"уаһоо.com", false, true, "yahoo.com"},
+		{"pаypаl.com", true, false, "paypal.com"},
+		{"テスト.jp", false, false, "テスト.jp"},
+		{"xn--zckzah.jp", false, false, "テスト.jp"},
+	}
+	for _, tt := range tests {
+		l := analyzeLookalike(tt.name)
+		if l.mixed != tt.mixed || l.wholeFake != tt.wholeFake || l.latin != tt.latin {
+			t.Errorf("analyzeLookalike(%q) = mixed %v, wholeFake %v, latin %q; want %v, %v, %q",
+				tt.name, l.mixed, l.wholeFake, l.latin, tt.mixed, tt.wholeFake, tt.latin)
+		}
+	}
+}
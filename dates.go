@@ -0,0 +1,206 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const defaultDateFormat = "2006-01-02 15:04:05 MST"
+
+// レジストリごとに異なる日付表記を受け付ける（先に一致したものを採用）
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"2006. 01. 02.",
+	"20060102150405",
+	"20060102",
+	"02-Jan-2006 15:04:05 MST",
+	"02-Jan-2006 15:04:05",
+	"02-Jan-2006",
+	"2-Jan-2006",
+	"02-January-2006",
+	"02 Jan 2006 15:04:05 MST",
+	"02 Jan 2006",
+	"2 January 2006",
+	"January 2 2006",
+	"January 2, 2006",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	time.UnixDate,
+	time.RFC1123,
+	time.RFC1123Z,
+	"Mon, 02 Jan 2006 15:04:05", // RFC1123 の末尾の "GMT" を除いた形
+	time.RFC850,
+	"Mon Jan 2 2006",
+}
+
+var (
+	// "(JST)" や "UTC" など末尾のゾーン表記
+	dateZoneSuffixRe = regexp.MustCompile(`\s*\(?\b(JST|UTC|GMT)\)?$`)
+	// "2025-08-14T04:00:00Z (YYYY-MM-DDThh:mm:ssZ)" のような注記
+	dateNoteRe  = regexp.MustCompile(`\s+\([A-Za-z:\-/ ]+\)$`)
+	dateSpaceRe = regexp.MustCompile(`\s+`)
+)
+
+var jstZone = time.FixedZone("JST", 9*60*60)
+
+// 書式の MST で受け付けるゾーンの略称と UTC からの時差（秒）。
+// time.Parse は知らない略称を時差 0 の架空のゾーンとして読むため、ここにない略称の日付は解釈しない。
+// CST・IST・BST のように複数の地域で使われる略称も含めない
+var dateZoneOffsets = map[string]int{
+	"UTC": 0, "GMT": 0, "JST": 9 * 3600, "KST": 9 * 3600,
+	"WET": 0, "WEST": 1 * 3600, "CET": 1 * 3600, "CEST": 2 * 3600, "EET": 2 * 3600, "EEST": 3 * 3600,
+	"EST": -5 * 3600, "EDT": -4 * 3600, "MST": -7 * 3600, "MDT": -6 * 3600, "PST": -8 * 3600, "PDT": -7 * 3600,
+}
+
+// withKnownZone は略称付きで読んだ時刻を、略称に対応する時差の時刻にする。知らない略称なら false
+func withKnownZone(t time.Time) (time.Time, bool) {
+	name, _ := t.Zone()
+	off, ok := dateZoneOffsets[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(name, off)), true
+}
+
+func parseDate(s string) (time.Time, bool) {
+	return parseDateIn(s, time.UTC)
+}
+
+// parseDateIn はゾーン表記のない日付を loc の時刻として解釈する
+func parseDateIn(s string, loc *time.Location) (time.Time, bool) {
+	s = dateSpaceRe.ReplaceAllString(strings.TrimSpace(s), " ")
+	if s == "" {
+		return time.Time{}, false
+	}
+	// "(JST)" は注記と同じ形なので、注記を除く前にゾーンとして読む
+	if m := dateZoneSuffixRe.FindStringSubmatch(s); m != nil {
+		s = strings.TrimSpace(s[:len(s)-len(m[0])])
+		if m[1] == "JST" {
+			loc = jstZone
+		} else {
+			loc = time.UTC
+		}
+	}
+	s = dateNoteRe.ReplaceAllString(s, "")
+	s = strings.TrimSuffix(s, ".")
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(layout, "MST") {
+			return withKnownZone(t)
+		}
+		return t, true
+	}
+	// "2006. 01. 02." は末尾のピリオドを落とした後も試す
+	if t, err := time.ParseInLocation("2006. 01. 02", s, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+type dateDisplay struct {
+	loc      *time.Location
+	layout   string
+	relative bool
+}
+
+var dateSettings = dateDisplay{loc: time.Local, layout: defaultDateFormat}
+
+func setDateDisplay(tz, layout string, relative bool) error {
+	dateSettings = dateDisplay{loc: time.Local, layout: defaultDateFormat, relative: relative}
+	if layout != "" {
+		dateSettings.layout = layout
+	}
+	switch strings.ToLower(tz) {
+	case "", "local":
+	case "utc":
+		dateSettings.loc = time.UTC
+	case "jst":
+		dateSettings.loc = jstZone
+	default:
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return err
+		}
+		dateSettings.loc = loc
+	}
+	return nil
+}
+
+func formatDate(t time.Time) string {
+	s := t.In(dateSettings.loc).Format(dateSettings.layout)
+	if dateSettings.relative {
+		s += " (" + relativeDate(t, time.Now()) + ")"
+	}
+	return s
+}
+
+// displayDate は日付として解釈できる値を表示用の形式に揃える。解釈できなければそのまま返す
+func displayDate(val string) string {
+	return displayDateIn(val, time.UTC)
+}
+
+// displayDateIn はゾーン表記のない値を loc の時刻として表示する（JPRS は JST）
+func displayDateIn(val string, loc *time.Location) string {
+	t, ok := parseDateIn(val, loc)
+	if !ok {
+		return val
+	}
+	return formatDate(t)
+}
+
+func relativeDate(t, now time.Time) string {
+	d := t.Sub(now)
+	future := d >= 0
+	if !future {
+		d = -d
+	}
+	days := int(d.Hours() / 24)
+	var span string
+	switch {
+	case days == 0:
+		return msg("date.today")
+	case days < 60:
+		span = plural(days, "date.day", "date.days")
+	case days < 730:
+		span = plural(days/30, "date.month", "date.months")
+	default:
+		span = plural(days/365, "date.year", "date.years")
+	}
+	if future {
+		return msg("date.future", span)
+	}
+	return msg("date.past", span)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return msg(one, n)
+	}
+	return msg(many, n)
+}
@@ -0,0 +1,63 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // RFC3339 の UTC。空なら解釈できないこと
+	}{
+		{"2024/07/01 01:05:07 (JST)", "2024-06-30T16:05:07Z"},
+		{"2024/07/01 (JST)", "2024-06-30T15:00:00Z"},
+		{"2024-07-01 01:05:07 JST", "2024-06-30T16:05:07Z"},
+		{"2024-07-01 01:05:07 (UTC)", "2024-07-01T01:05:07Z"},
+		{"2024-07-01T01:05:07Z", "2024-07-01T01:05:07Z"},
+		{"2024-07-01T01:05:07+09:00", "2024-06-30T16:05:07Z"},
+		{"2025-08-14T04:00:00Z (YYYY-MM-DDThh:mm:ssZ)", "2025-08-14T04:00:00Z"},
+		{"01-Jul-2024", "2024-07-01T00:00:00Z"},
+		{"01-Jul-2024 01:05:07 UTC", "2024-07-01T01:05:07Z"},
+		{"20240701", "2024-07-01T00:00:00Z"},
+		{"2024. 07. 01.", "2024-07-01T00:00:00Z"},
+		{"2024-07-01 01:05:07 CEST", "2024-06-30T23:05:07Z"},
+		{"01-Jul-2024 01:05:07 EST", "2024-07-01T06:05:07Z"},
+		{"Thu Jul 11 01:05:07 PDT 2024", "2024-07-11T08:05:07Z"},
+		{"Thu, 11 Jul 2024 01:05:07 GMT", "2024-07-11T01:05:07Z"},
+		// 知らない略称・複数の地域で使われる略称は UTC として読まずに解釈しない
+		{"2024-07-01 01:05:07 XYZ", ""},
+		{"2024-07-01 01:05:07 CST", ""},
+		{"Thu Jul 11 01:05:07 ABC 2024", ""},
+		{"not a date", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.in)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("parseDate(%q) = %v, want failure", tt.in, got)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("parseDate(%q) failed, want %s", tt.in, tt.want)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339); s != tt.want {
+			t.Errorf("parseDate(%q) = %s, want %s", tt.in, s, tt.want)
+		}
+	}
+}
+
+func TestParseDateInDefaultZone(t *testing.T) {
+	// ゾーン表記のない日付は指定した場所の時刻として読む
+	got, ok := parseDateIn("2024/07/01 01:05:07", jstZone)
+	if !ok || got.UTC().Format(time.RFC3339) != "2024-06-30T16:05:07Z" {
+		t.Errorf("parseDateIn(JST) = %v, %v", got, ok)
+	}
+}
@@ -0,0 +1,105 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"strings"
+)
+
+// RFC 5731 / ICANN で定義されている EPP ステータスコード
+var eppCodes = []string{
+	"ok", "active", "inactive",
+	"addPeriod", "autoRenewPeriod", "renewPeriod", "transferPeriod", "redemptionPeriod",
+	"pendingCreate", "pendingDelete", "pendingRenew", "pendingRestore", "pendingTransfer", "pendingUpdate",
+	"clientDeleteProhibited", "clientHold", "clientRenewProhibited", "clientTransferProhibited", "clientUpdateProhibited",
+	"serverDeleteProhibited", "serverHold", "serverRenewProhibited", "serverTransferProhibited", "serverUpdateProhibited",
+}
+
+// 削除・名前解決停止につながる状態
+var riskyEPPCodes = map[string]bool{
+	"pendingDelete":    true,
+	"redemptionPeriod": true,
+	"pendingRestore":   true,
+	"serverHold":       true,
+	"clientHold":       true,
+}
+
+var eppGroupOrder = []string{"state", "client", "server", "pending"}
+
+var eppLookup = func() map[string]string {
+	m := make(map[string]string, len(eppCodes))
+	for _, c := range eppCodes {
+		m[strings.ToLower(c)] = c
+	}
+	return m
+}()
+
+// eppCode は "clientTransferProhibited https://icann.org/epp#..." や
+// "CLIENT TRANSFER PROHIBITED" のような表記を正規のコード名にする
+func eppCode(s string) (string, bool) {
+	f := strings.Fields(s)
+	if len(f) > 0 && strings.HasPrefix(f[len(f)-1], "http") {
+		f = f[:len(f)-1]
+	}
+	if len(f) == 0 {
+		return "", false
+	}
+	if code, ok := eppLookup[strings.ToLower(f[0])]; ok && len(f) == 1 {
+		return code, true
+	}
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(strings.Join(f, "")))
+	code, ok := eppLookup[key]
+	return code, ok
+}
+
+func eppGroup(code string) string {
+	switch {
+	case strings.HasPrefix(code, "client"):
+		return "client"
+	case strings.HasPrefix(code, "server"):
+		return "server"
+	case strings.HasPrefix(code, "pending"), strings.HasSuffix(code, "Period"):
+		return "pending"
+	}
+	return "state"
+}
+
+// statusRole はステータスの表示色の役割を返す
+func statusRole(code string) string {
+	if riskyEPPCodes[code] {
+		return "status-risk"
+	}
+	return "status-ok"
+}
+
+// statusKVs はステータスを client / server / pending ごとにまとめ、説明を付けて返す
+func statusKVs(rec *Record, lang string) []KV {
+	groups := map[string][]KV{}
+	for _, st := range rec.Status {
+		code, ok := eppCode(st)
+		if !ok {
+			groups["state"] = append(groups["state"], KV{Val: st})
+			continue
+		}
+		g := eppGroup(code)
+		groups[g] = append(groups[g], KV{
+			Val:  code + " " + tableBorder.dash() + " " + msg("epp."+code),
+			Role: statusRole(code),
+		})
+	}
+	var kvs []KV
+	for _, g := range eppGroupOrder {
+		for i, kv := range groups[g] {
+			if i == 0 {
+				kv.Key = msg("epp.group." + g)
+				if g == "state" {
+					kv.Key = translateLabel("Domain Status", lang)
+				}
+			}
+			kvs = append(kvs, kv)
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,390 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultExportFields = "domain,registrar,created,expiry,status,nameservers"
+
+// 複数値フィールド（status, nameservers など）を1セルにまとめる区切り
+const multiValueSep = ";"
+
+// -fields / -field に指定できる列名（fieldValues と同じ順）
+var exportFieldNames = []string{
+	"query", "domain", "domain_unicode", "registrar", "registrar_url", "registrar_iana_id", "whois_server",
+	"created", "updated", "expiry", "status", "nameservers", "dnssec",
+	"organization", "registrant", "server", "availability", "kind", "error",
+}
+
+// -output に指定できる形式
+var outputFormats = []string{"conventional", "table", "raw", "json", "csv", "tsv", "ndjson", "yaml", "markdown"}
+
+var exportFormats = map[string]bool{"csv": true, "tsv": true, "ndjson": true, "yaml": true, "markdown": true}
+
+func jsonTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// recordFields は Record の MarshalJSON を引き継がない別名
+type recordFields Record
+
+// recordJSON は JSON・YAML に出力するレコードの形（日時は RFC3339、未設定なら省く）
+type recordJSON struct {
+	recordFields
+	DomainUnicode string `json:"domain_unicode,omitempty"`
+	Created       string `json:"created,omitempty"`
+	Updated       string `json:"updated,omitempty"`
+	Expiry        string `json:"expiry,omitempty"`
+}
+
+func (r Record) toJSON() recordJSON {
+	return recordJSON{recordFields(r), r.domainUnicode(), jsonTime(r.Created), jsonTime(r.Updated), jsonTime(r.Expiry)}
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON())
+}
+
+// errorJSON は検索に失敗した名前の JSON・YAML の1件
+type errorJSON struct {
+	Query string `json:"query"`
+	Error string `json:"error"`
+}
+
+// domainUnicode は国際化ドメイン名の Unicode 表記を返す（ASCII のドメイン名なら空）
+func (r Record) domainUnicode() string {
+	d := r.Domain
+	if d == "" {
+		d = r.Query
+	}
+	if u, ok := unicodeDomain(d); ok {
+		return u
+	}
+	return ""
+}
+
+// fieldValues は -fields で指定できる列名からレコードの値を取り出す
+func fieldValues(rec *Record, name string) ([]string, bool) {
+	single := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return []string{s}
+	}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "query":
+		return single(rec.Query), true
+	case "domain":
+		if rec.Domain == "" {
+			return single(rec.Query), true
+		}
+		return single(rec.Domain), true
+	case "domain_unicode":
+		return single(rec.domainUnicode()), true
+	case "registrar":
+		return single(rec.Registrar), true
+	case "registrar_url":
+		return single(rec.RegistrarURL), true
+	case "registrar_iana_id":
+		return single(rec.RegistrarIANAID), true
+	case "whois_server":
+		return single(rec.WhoisServer), true
+	case "created":
+		return single(jsonTime(rec.Created)), true
+	case "updated":
+		return single(jsonTime(rec.Updated)), true
+	case "expiry":
+		return single(jsonTime(rec.Expiry)), true
+	case "status":
+		return statusCodes(rec), true
+	case "nameservers":
+		return rec.NameServers, true
+	case "dnssec":
+		return single(rec.DNSSEC), true
+	case "organization":
+		return single(rec.Organization), true
+	case "registrant":
+		return single(registrantOrg(rec)), true
+	case "availability":
+		return single(availability(rec, nil)), true
+	case "kind":
+		// squat の候補の種類。値は squat の出力（checkWriter）でだけ入る
+		return nil, true
+	case "server":
+		if len(rec.Chain) == 0 {
+			return nil, true
+		}
+		return single(rec.Chain[len(rec.Chain)-1].Server), true
+	}
+	return nil, false
+}
+
+func parseFieldList(s string) ([]string, error) {
+	var fields []string
+	probe := &Record{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := fieldValues(probe, f); !ok && f != "error" {
+			return nil, errors.New(msg("err.unknown_field", f))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+type recordWriter interface {
+	Write(rec *Record, err error) error
+	Flush() error
+}
+
+func newRecordWriter(format string, w io.Writer, fields []string, lang string) recordWriter {
+	switch format {
+	case "yaml":
+		return &yamlWriter{w: bufio.NewWriter(w)}
+	case "markdown":
+		return &markdownWriter{w: bufio.NewWriter(w), lang: lang, verbose: *verboseFlag}
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w), fields: fields}
+	case "tsv":
+		return &tsvWriter{w: bufio.NewWriter(w), fields: fields}
+	default:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}
+	}
+}
+
+func rowValues(rec *Record, err error, fields []string) []string {
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		switch {
+		case f == "error":
+			if err != nil {
+				row[i] = err.Error()
+			}
+		case f == "availability":
+			row[i] = availability(rec, err)
+		case f == "query" || f == "domain":
+			vals, _ := fieldValues(rec, f)
+			row[i] = strings.Join(vals, multiValueSep)
+		case err == nil:
+			vals, _ := fieldValues(rec, f)
+			row[i] = strings.Join(vals, multiValueSep)
+		}
+	}
+	return row
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	fields  []string
+	started bool
+}
+
+func (c *csvWriter) Write(rec *Record, err error) error {
+	return c.writeCells(rowValues(rec, err, c.fields))
+}
+
+// writeCells は最初の1行の前に列名の行を書いてから cells を書く
+func (c *csvWriter) writeCells(cells []string) error {
+	if !c.started {
+		c.started = true
+		if e := c.w.Write(c.fields); e != nil {
+			return e
+		}
+	}
+	if e := c.w.Write(cells); e != nil {
+		return e
+	}
+	// 1行ごとに書き出し、大量件数でもメモリに溜めない
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type tsvWriter struct {
+	w       *bufio.Writer
+	fields  []string
+	started bool
+}
+
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func (t *tsvWriter) writeRow(cells []string) error {
+	for i := range cells {
+		cells[i] = tsvEscaper.Replace(cells[i])
+	}
+	if _, err := t.w.WriteString(strings.Join(cells, "\t") + "\n"); err != nil {
+		return err
+	}
+	return t.w.Flush()
+}
+
+func (t *tsvWriter) Write(rec *Record, err error) error {
+	return t.writeCells(rowValues(rec, err, t.fields))
+}
+
+// writeCells は最初の1行の前に列名の行を書いてから cells を書く
+func (t *tsvWriter) writeCells(cells []string) error {
+	if !t.started {
+		t.started = true
+		if e := t.writeRow(append([]string(nil), t.fields...)); e != nil {
+			return e
+		}
+	}
+	return t.writeRow(cells)
+}
+
+func (t *tsvWriter) Flush() error { return t.w.Flush() }
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+func (n *ndjsonWriter) Write(rec *Record, err error) error {
+	if err != nil {
+		return n.writeValue(errorJSON{rec.Query, err.Error()})
+	}
+	return n.writeValue(rec)
+}
+
+// writeValue は v を JSON の1行として書く
+func (n *ndjsonWriter) writeValue(v any) error {
+	b, e := json.Marshal(v)
+	if e != nil {
+		return e
+	}
+	if _, e := n.w.Write(append(b, '\n')); e != nil {
+		return e
+	}
+	return n.w.Flush()
+}
+
+func (n *ndjsonWriter) Flush() error { return n.w.Flush() }
+
+// forEachName は引数と -f ファイル（"-" で標準入力）の名前を1件ずつ渡す
+func forEachName(args []string, file string, fn func(name string)) error {
+	for _, a := range args {
+		fn(a)
+	}
+	if file == "" {
+		return nil
+	}
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fn(line)
+	}
+	return scanner.Err()
+}
+
+// runExport は検索に失敗した名前の数を返す（失敗した名前もエラーの行として出力する）
+func runExport(format string, args []string, config Config) (int, error) {
+	return exportEach(format, config, func(fn func(name string)) error {
+		return forEachName(args, *listFileFlag, fn)
+	}, nil)
+}
+
+// exportEach は names が渡す名前を1件ずつ検索し、format の形式で逐次出力する（bulk・squat・sweep で共用）。
+// wrap が nil でなければ出力する writer を包む（squat・sweep が項目を加える）。
+// 検索に失敗した名前は標準エラーに表示して数え、その数を返す
+func exportEach(format string, config Config, names func(fn func(name string)) error, wrap func(recordWriter) recordWriter) (int, error) {
+	fieldList := *fieldsFlag
+	if *fieldFlag != "" {
+		fieldList = *fieldFlag
+	}
+	fields, err := parseFieldList(fieldList)
+	if err != nil {
+		return 0, err
+	}
+	var steps []pathStep
+	if format == "query" {
+		if steps, err = parsePath(*queryFlag); err != nil {
+			return 0, err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var rw recordWriter
+	switch format {
+	case "query":
+		rw = &queryWriter{w: bufio.NewWriter(w), steps: steps}
+	case "fields":
+		rw = &fieldsWriter{w: bufio.NewWriter(w), fields: fields}
+	default:
+		rw = newRecordWriter(format, w, fields, config.Lang)
+	}
+	if wrap != nil {
+		rw = wrap(rw)
+	}
+	opts := flagLookupOptions(config)
+	failed := 0
+	var writeErr error
+	err = names(func(name string) {
+		if writeErr != nil {
+			return
+		}
+		domain, lerr := normalizeDomain(name)
+		if lerr != nil {
+			fmt.Fprintln(os.Stderr, lerr)
+			failed++
+			writeErr = rw.Write(&Record{Query: name}, lerr)
+			return
+		}
+		rec, lerr := lookup(domain, opts)
+		if lerr != nil {
+			fmt.Fprintln(os.Stderr, msg("err.lookup", domain, lerr))
+			failed++
+			rec = &Record{Query: domain}
+		}
+		writeErr = rw.Write(rec, lerr)
+	})
+	if writeErr != nil {
+		return failed, writeErr
+	}
+	if err != nil {
+		return failed, err
+	}
+	return failed, rw.Flush()
+}
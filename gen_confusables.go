@@ -0,0 +1,114 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+//go:build ignore
+
+// gen_confusables は Unicode の confusables.txt（UTS #39）から data/confusables.tsv を作る。
+// confusable.go の go:generate から実行する
+//
+//	go run gen_confusables.go [-src <URL またはファイル>] [-o data/confusables.tsv]
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const confusablesURL = "https://www.unicode.org/Public/security/latest/confusables.txt"
+
+func main() {
+	src := flag.String("src", confusablesURL, "confusables.txt の URL またはファイル")
+	out := flag.String("o", "data/confusables.tsv", "出力するファイル")
+	flag.Parse()
+	if err := generate(*src, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "gen_confusables:", err)
+		os.Exit(1)
+	}
+}
+
+func open(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(src)
+	}
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func generate(src, out string) error {
+	r, err := open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// 先頭のコメントのうち、日付・版・利用条件の行を出力にも残す
+	var header []string
+	rows := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		if strings.HasPrefix(line, "#") {
+			for _, p := range []string{"# Date:", "# Version:", "# ©", "# For terms of use"} {
+				if strings.HasPrefix(line, p) {
+					header = append(header, line)
+				}
+			}
+			continue
+		}
+		// 0441 ;	0063 ;	MA	# ( с → c ) CYRILLIC SMALL LETTER ES → LATIN SMALL LETTER C
+		fields := strings.Split(strings.SplitN(line, "#", 2)[0], ";")
+		if len(fields) < 2 {
+			continue
+		}
+		from := strings.TrimSpace(fields[0])
+		to := strings.Join(strings.Fields(fields[1]), " ")
+		if from == "" || to == "" || strings.Contains(from, " ") {
+			return fmt.Errorf("unexpected line %q", line)
+		}
+		rows[from] = to
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%s: no mappings", src)
+	}
+
+	keys := make([]string, 0, len(rows))
+	for k := range rows {
+		keys = append(keys, k)
+	}
+	// 16進の桁数が違うのでコードポイントの順に並べる
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	var b strings.Builder
+	b.WriteString("# gen_confusables.go で confusables.txt から生成したファイル。手で編集しない\n")
+	fmt.Fprintf(&b, "# 生成元: %s\n", src)
+	for _, h := range header {
+		b.WriteString(h + "\n")
+	}
+	b.WriteString("# 形式: 文字<TAB>原型（16進のコードポイント。原型は空白区切りの並び）\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s\t%s\n", k, rows[k])
+	}
+	return os.WriteFile(out, []byte(b.String()), 0o644)
+}
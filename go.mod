@@ -5,9 +5,12 @@ go 1.23.2
 require (
 	github.com/mattn/go-runewidth v0.0.16
 	golang.org/x/net v0.43.0
+	golang.org/x/term v0.34.0
+	golang.org/x/text v0.28.0
 )
 
 require (
 	github.com/rivo/uniseg v0.2.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/tools v0.35.0 // indirect
 )
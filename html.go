@@ -0,0 +1,142 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/report.html
+var reportHTML string
+
+type reportRow struct {
+	Domain      string
+	Anchor      string
+	Registrar   string
+	Expiry      string
+	ExpirySort  string // 並べ替え用の RFC3339（表示は設定した書式なので並びが崩れる）
+	DaysLeft    string
+	DaysSort    int
+	Status      []string
+	NameServers []string
+	Class       string
+	Error       string
+	Hops        []Hop
+}
+
+type reportHeaders struct {
+	Domain, Registrar, Expiry, DaysLeft, Status, NameServers string
+}
+
+type reportPage struct {
+	Lang           string
+	Title          string
+	Generated      string
+	GeneratedLabel string
+	CountLabel     string
+	RawLabel       string
+	Headers        reportHeaders
+	Rows           []reportRow
+}
+
+// 有効期限までの日数で色分けする（期限切れ / 30日以内 / 90日以内 / それ以外）
+func expiryClass(rec *Record) string {
+	if rec.Expiry.IsZero() {
+		return ""
+	}
+	switch d := daysUntil(rec.Expiry); {
+	case d < 0:
+		return "expired"
+	case d <= 30:
+		return "critical"
+	case d <= 90:
+		return "warning"
+	}
+	return "ok"
+}
+
+func newReportRow(i int, name string, rec *Record, err error) reportRow {
+	row := reportRow{Domain: displayIDN(name), Anchor: "d" + strconv.Itoa(i)}
+	if err != nil {
+		row.Error = msg("compare.error", err)
+		row.Class = "error"
+		return row
+	}
+	row.Registrar = rec.Registrar
+	row.Status = statusCodes(rec)
+	for _, ns := range rec.NameServers {
+		row.NameServers = append(row.NameServers, displayIDN(ns))
+	}
+	row.Class = expiryClass(rec)
+	row.DaysSort = 1 << 30
+	if !rec.Expiry.IsZero() {
+		// CLI の表示と同じタイムゾーン・書式にそろえる
+		row.Expiry = formatDate(rec.Expiry)
+		row.ExpirySort = rec.Expiry.UTC().Format(time.RFC3339)
+		row.DaysSort = daysUntil(rec.Expiry)
+		row.DaysLeft = strconv.Itoa(row.DaysSort)
+	}
+	for _, h := range rec.Chain {
+		h.Raw = strings.ReplaceAll(h.Raw, "\r", "")
+		row.Hops = append(row.Hops, h)
+	}
+	return row
+}
+
+// whois report -html out.html -f domains.txt [name ...]
+func runReport(args []string, config Config) error {
+	t, err := template.New("report").Parse(reportHTML)
+	if err != nil {
+		return err
+	}
+
+	page := reportPage{
+		Lang:           config.Lang,
+		Title:          msg("report.title"),
+		Generated:      time.Now().Format("2006-01-02 15:04:05 MST"),
+		GeneratedLabel: msg("report.generated"),
+		CountLabel:     msg("report.count"),
+		RawLabel:       msg("report.raw"),
+		Headers: reportHeaders{
+			Domain:      translateLabel("Domain Name", config.Lang),
+			Registrar:   translateLabel("Registrar", config.Lang),
+			Expiry:      translateLabel("Registry Expiry Date", config.Lang),
+			DaysLeft:    msg("report.days_left"),
+			Status:      translateLabel("Domain Status", config.Lang),
+			NameServers: translateLabel("Name Server", config.Lang),
+		},
+	}
+
+	opts := flagLookupOptions(config)
+	err = forEachName(args, *listFileFlag, func(name string) {
+		domain, lerr := normalizeDomain(name)
+		if lerr != nil {
+			fmt.Fprintln(os.Stderr, lerr)
+			page.Rows = append(page.Rows, newReportRow(len(page.Rows), name, nil, lerr))
+			return
+		}
+		rec, lerr := lookup(domain, opts)
+		if lerr != nil {
+			fmt.Fprintln(os.Stderr, msg("err.lookup", domain, lerr))
+		}
+		page.Rows = append(page.Rows, newReportRow(len(page.Rows), domain, rec, lerr))
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*htmlFlag)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return t.Execute(f, page)
+}
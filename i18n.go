@@ -0,0 +1,167 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+type Catalog struct {
+	Lang     string            `json:"lang"`
+	Messages map[string]string `json:"messages"`
+	Labels   map[string]string `json:"labels"`
+}
+
+var (
+	currentLang  = "en"
+	localeDirs   []string
+	catalogCache = map[string]*Catalog{}
+)
+
+func normalizeLang(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if lang == "c" || lang == "posix" {
+		return ""
+	}
+	return lang
+}
+
+// 優先順位: -lang > config.json の lang > LC_ALL > LANG > en
+func resolveLang(flagLang, configLang string) string {
+	for _, l := range []string{flagLang, configLang, os.Getenv("LC_ALL"), os.Getenv("LANG")} {
+		if n := normalizeLang(l); n != "" {
+			return n
+		}
+	}
+	return "en"
+}
+
+func setLocale(lang string, dirs []string) {
+	localeDirs = dirs
+	catalogCache = map[string]*Catalog{}
+	currentLang = lang
+}
+
+func readCatalog(lang string) *Catalog {
+	var merged *Catalog
+	apply := func(b []byte) {
+		var c Catalog
+		if err := json.Unmarshal(b, &c); err != nil {
+			return
+		}
+		if merged == nil {
+			merged = &Catalog{Lang: lang, Messages: map[string]string{}, Labels: map[string]string{}}
+		}
+		for k, v := range c.Messages {
+			merged.Messages[k] = v
+		}
+		for k, v := range c.Labels {
+			merged.Labels[k] = v
+		}
+	}
+	if b, err := localeFS.ReadFile("locales/" + lang + ".json"); err == nil {
+		apply(b)
+	}
+	// 外部ファイルは組み込みカタログを上書きする
+	for _, dir := range localeDirs {
+		if dir == "" {
+			continue
+		}
+		if b, err := os.ReadFile(filepath.Join(dir, lang+".json")); err == nil {
+			apply(b)
+		}
+	}
+	return merged
+}
+
+func catalogFor(lang string) *Catalog {
+	lang = normalizeLang(lang)
+	if c, ok := catalogCache[lang]; ok {
+		return c
+	}
+	c := readCatalog(lang)
+	if c == nil {
+		if i := strings.Index(lang, "-"); i > 0 {
+			c = catalogFor(lang[:i])
+		}
+	}
+	catalogCache[lang] = c
+	return c
+}
+
+func msg(id string, args ...any) string {
+	text := id
+	if c := catalogFor(currentLang); c != nil && c.Messages[id] != "" {
+		text = c.Messages[id]
+	} else if c := catalogFor("en"); c != nil && c.Messages[id] != "" {
+		text = c.Messages[id]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// availableLangs は組み込み・追加ディレクトリのカタログから選べる言語コードを返す
+func availableLangs() []string {
+	seen := map[string]bool{}
+	var langs []string
+	add := func(path string) {
+		lang := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	if files, err := fs.Glob(localeFS, "locales/*.json"); err == nil {
+		for _, f := range files {
+			add(f)
+		}
+	}
+	for _, dir := range localeDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, f := range files {
+			add(f)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// hasMsg はメッセージが現在の言語か英語のカタログに定義されているかを返す
+func hasMsg(id string) bool {
+	return msg(id) != id
+}
+
+func translateLabel(label, lang string) string {
+	if c := catalogFor(lang); c != nil {
+		if l, ok := c.Labels[label]; ok && l != "" {
+			return l
+		}
+	}
+	return label
+}
+
+func hasLabel(label string) bool {
+	if c := catalogFor("ja"); c != nil {
+		_, ok := c.Labels[label]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// 国際化ドメイン名の表示形式（config.json の idn_display / -unicode / -punycode。prepare で設定する）
+//
+//	both:     xn--eckwd4c7c.xn--zckzah (テスト.テスト)
+//	unicode:  テスト.テスト
+//	punycode: xn--eckwd4c7c.xn--zckzah
+var idnDisplay = "both"
+
+var idnDisplayModes = []string{"both", "unicode", "punycode"}
+
+// ACE ラベル（xn--）を含むドメイン名らしい語
+var aceDomainRe = regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)*xn--[a-z0-9-]+(?:\.[a-z0-9-]+)*\.?`)
+
+// 問い合わせ用の変換。空のラベルや長すぎるラベルも誤りとして扱う
+var idnaLookup = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.VerifyDNSLength(true))
+
+// normalizeDomain は入力を問い合わせに使う ASCII の小文字にする。
+// 日本語ドメイン名などは punycode に変換し、変換できない場合はそのまま問い合わせずにエラーを返す。
+// 末尾のドットは除く
+func normalizeDomain(input string) (string, error) {
+	s := strings.TrimSpace(input)
+	name := strings.TrimSuffix(s, ".")
+	// xn-- を含む名前も punycode として正しいかを検査する（"xn--a.com" などは誤り）
+	if !hasNonASCII(name) && !strings.Contains(strings.ToLower(name), "xn--") {
+		return strings.ToLower(name), nil
+	}
+	ascii, err := idnaLookup.ToASCII(name)
+	if err != nil {
+		return "", &idnError{input: s, err: err}
+	}
+	return strings.ToLower(ascii), nil
+}
+
+// idnError は punycode に変換できなかった入力とその理由
+type idnError struct {
+	input string
+	err   error
+}
+
+func (e *idnError) Error() string { return msg("err.idna", e.input, e.err) }
+func (e *idnError) Unwrap() error { return e.err }
+
+func hasNonASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// unicodeDomain は xn-- を含むドメイン名を Unicode 表記にする。変換できなければ false を返す
+func unicodeDomain(s string) (string, bool) {
+	u, err := idna.Display.ToUnicode(strings.TrimSuffix(s, "."))
+	if err != nil || strings.EqualFold(u, strings.TrimSuffix(s, ".")) {
+		return s, false
+	}
+	return u, true
+}
+
+// displayIDN は値に含まれる punycode のドメイン名を idnDisplay の形式で表示する
+func displayIDN(val string) string {
+	if idnDisplay == "punycode" || !strings.Contains(strings.ToLower(val), "xn--") {
+		return val
+	}
+	return aceDomainRe.ReplaceAllStringFunc(val, func(d string) string {
+		u, ok := unicodeDomain(d)
+		switch {
+		case !ok:
+			return d
+		case idnDisplay == "unicode":
+			return u
+		}
+		return d + " (" + u + ")"
+	})
+}
@@ -0,0 +1,273 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const jprsServer = "whois.jprs.jp:43"
+
+// JPRS WHOIS の検索タイプ（DOM: ドメイン, NET: ネットワーク, HOST: ネームサーバ, CON: 担当者ハンドル）
+var jprsQueryTypes = map[string]string{
+	"dom":  "DOM",
+	"net":  "NET",
+	"host": "HOST",
+	"con":  "CON",
+}
+
+// "a. [ドメイン名]  EXAMPLE.CO.JP" / "[状態]  Active" の両形式に対応
+var jprsLineRe = regexp.MustCompile(`^(?:[a-z]\.\s*)?\[([^\]]+)\]\s*(.*)$`)
+
+var jprsStateDateRe = regexp.MustCompile(`\((\d{4}/\d{2}/\d{2})\)`)
+
+var jprsHandleRe = regexp.MustCompile(`^[A-Z0-9]+JP$`)
+
+func isJPRSServer(server string) bool {
+	return strings.Contains(strings.ToLower(server), "jprs.jp")
+}
+
+func isJPRSResponse(raw string) bool {
+	return strings.Contains(raw, "JPRS database") ||
+		strings.Contains(raw, "Domain Information:") ||
+		strings.Contains(raw, "Contact Information:")
+}
+
+func jprsQuery(qtype, name, lang string) (string, error) {
+	query := name
+	if qtype != "" {
+		t, ok := jprsQueryTypes[strings.ToLower(qtype)]
+		if !ok {
+			return "", errors.New(msg("err.jprs_type", qtype))
+		}
+		if t == "CON" {
+			name = strings.ToUpper(name)
+		}
+		query = t + " " + name
+	}
+	// 日本語以外の表示言語では英語表記で返してもらう
+	if normalizeLang(lang) != "ja" {
+		query += "/e"
+	}
+	return query, nil
+}
+
+func parseJPRSLine(l string) (key, val string, ok bool) {
+	m := jprsLineRe.FindStringSubmatch(l)
+	if m == nil {
+		return "", "", false
+	}
+	key = strings.TrimSpace(m[1])
+	if en, found := jprsKeys[key]; found {
+		key = en
+	}
+	return key, strings.TrimSpace(m[2]), true
+}
+
+func parseJPRS(raw string) *Record {
+	rec := &Record{Raw: raw}
+	var cur *Contact
+	inContact := false
+	lastKey := ""
+
+	lines := strings.Split(raw, "\n")
+	for i := 0; i < len(lines); i++ {
+		l := strings.TrimSpace(strings.TrimRight(lines[i], "\r"))
+		if l == "" {
+			continue
+		}
+		if strings.HasPrefix(l, "Domain Information:") {
+			inContact = false
+			continue
+		}
+		if strings.HasPrefix(l, "Contact Information:") {
+			inContact = true
+			role := "contact"
+			if strings.Contains(l, "公開連絡窓口") {
+				role = "registrant"
+			}
+			rec.Contacts = append(rec.Contacts, Contact{Role: role})
+			cur = &rec.Contacts[len(rec.Contacts)-1]
+			continue
+		}
+
+		key, val, ok := parseJPRSLine(l)
+		if !ok {
+			// 住所などの継続行は直前の項目に連結する
+			if lastKey != "" && strings.HasPrefix(lines[i], " ") {
+				if inContact && cur != nil {
+					if lastKey == "Postal Address" {
+						setJPRSContactField(cur, lastKey, l)
+					}
+				} else if len(rec.Fields) > 0 {
+					rec.Fields[len(rec.Fields)-1].Val += " " + l
+				}
+			}
+			continue
+		}
+		lastKey = ""
+		// 値が次行に折り返されている場合
+		if val == "" && i+1 < len(lines) {
+			next := strings.TrimSpace(strings.TrimRight(lines[i+1], "\r"))
+			if next != "" && jprsLineRe.FindStringSubmatch(next) == nil && !strings.HasSuffix(next, ":") {
+				val = next
+				i++
+			}
+		}
+		if val == "" {
+			continue
+		}
+		lastKey = key
+
+		// 連絡先ブロックの項目は Contacts 側にのみ保持する
+		if inContact && cur != nil {
+			setJPRSContactField(cur, key, val)
+			continue
+		}
+		rec.Fields = append(rec.Fields, KV{Key: key, Val: val})
+
+		switch key {
+		case "Domain Name":
+			rec.Domain = strings.ToLower(val)
+		case "Registrant":
+			if rec.Organization == "" {
+				rec.Organization = val
+			}
+		case "Organization":
+			rec.Organization = val
+		case "Organization Type":
+			rec.OrganizationType = val
+		case "Administrative Contact":
+			rec.contact("admin").Handle = val
+		case "Technical Contact":
+			rec.contact("tech").Handle = val
+		case "Name Server":
+			rec.addNameServer(val)
+		case "Signing Key":
+			rec.DNSSEC = val
+		case "Creation Date":
+			if t, ok := parseDateIn(val, jstZone); ok {
+				rec.Created = t
+			}
+		case "Registry Expiry Date":
+			if t, ok := parseDateIn(val, jstZone); ok {
+				rec.Expiry = t
+			}
+		case "Updated Date":
+			if t, ok := parseDateIn(val, jstZone); ok {
+				rec.Updated = t
+			}
+		case "Status":
+			// 属性型は "Connected (2025/03/31)" の形で有効期限を返す
+			if m := jprsStateDateRe.FindStringSubmatch(val); m != nil && rec.Expiry.IsZero() {
+				if t, ok := parseDateIn(m[1], jstZone); ok {
+					rec.Expiry = t
+				}
+			}
+			rec.addStatus(val)
+		}
+	}
+	return rec
+}
+
+func setJPRSContactField(c *Contact, key, val string) {
+	switch key {
+	case "JPNIC Handle":
+		c.Handle = val
+	case "Name", "Last, First":
+		if c.Name == "" {
+			c.Name = val
+		}
+	case "Organization":
+		if c.Organization == "" {
+			c.Organization = val
+		}
+	case "Division":
+		if c.Division == "" {
+			c.Division = val
+		}
+	case "Title":
+		if c.Title == "" {
+			c.Title = val
+		}
+	case "Email":
+		c.Email = val
+	case "Phone":
+		c.Phone = val
+	case "Fax":
+		c.Fax = val
+	case "Postal Code":
+		c.PostalCode = val
+	case "Postal Address":
+		if c.Address == "" {
+			c.Address = val
+		} else {
+			c.Address += " " + val
+		}
+	case "Web Page":
+		c.WebPage = val
+	case "Updated Date":
+		c.Updated = val
+	}
+}
+
+// 登録担当者・技術連絡担当者のハンドルを CON クエリで引き直し、連絡先情報を埋める
+func followJPRSHandles(rec *Record, server string, opts lookupOptions) {
+	for i := range rec.Contacts {
+		c := &rec.Contacts[i]
+		if c.Handle == "" || !jprsHandleRe.MatchString(strings.ToUpper(c.Handle)) {
+			continue
+		}
+		query, _ := jprsQuery("con", c.Handle, opts.Lang)
+		settings, _ := serverConfigForHost(server)
+		raw, err := queryWhois(server, query, settings.Charset, opts.Timeout, opts.Refresh)
+		if err != nil || raw == "" {
+			continue
+		}
+		rec.Chain = append(rec.Chain, Hop{Server: server, Query: query, Raw: raw})
+		resolved := parseJPRS(raw)
+		for _, rc := range resolved.Contacts {
+			role, handle := c.Role, c.Handle
+			*c = rc
+			c.Role = role
+			if c.Handle == "" {
+				c.Handle = handle
+			}
+			break
+		}
+	}
+}
+
+func contactKVs(rec *Record, lang string) []KV {
+	var kvs []KV
+	for _, c := range rec.Contacts {
+		if c.Handle == "" || (c.Name == "" && c.Email == "" && c.Organization == "") {
+			continue
+		}
+		prefix := translateLabel(contactRoleLabels[c.Role], lang) + " "
+		add := func(label, val string) {
+			if val != "" {
+				kvs = append(kvs, KV{Key: prefix + translateLabel(label, lang), Val: val})
+			}
+		}
+		add("Name", c.Name)
+		add("Organization", c.Organization)
+		add("Division", c.Division)
+		add("Email", c.Email)
+		add("Phone", c.Phone)
+		add("Fax", c.Fax)
+	}
+	return kvs
+}
+
+var contactRoleLabels = map[string]string{
+	"registrant": "Registrant Contact",
+	"admin":      "Administrative Contact",
+	"tech":       "Technical Contact",
+	"billing":    "Billing Contact",
+	"contact":    "Contact",
+}
@@ -6,34 +6,68 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mattn/go-runewidth"
-	"golang.org/x/net/idna"
 )
 
 const Version = "2.0.0"
 
-var rawFlag = flag.Bool("raw", false, "Output raw whois text")
-var versionFlag = flag.Bool("version", false, "Show version information")
-var helpFlag = flag.Bool("help", false, "Show help message")
-var outFile = flag.String("o", "", "Output to file")
-var serverFlag = flag.String("server", "", "Override WHOIS server host[:port]")
-var timeoutFlag = flag.Duration("timeout", 8*time.Second, "Network timeout (e.g. 5s, 2m)")
-var followFlag = flag.Bool("follow", true, "Follow referral WHOIS server if present")
-var noColorFlag = flag.Bool("nocolor", false, "Disable colored output")
-var tableFlag = flag.Bool("table", false, "Render output as a box-drawn table")
-var widthFlag = flag.Int("width", 0, "Table width (columns), default: 120 or $COLUMNS")
+// フラグの値。各サブコマンドが必要なものだけを自分の FlagSet に登録する（commands.go）
+var (
+	rawFlag           = new(bool)
+	versionFlag       = new(bool)
+	outFile           = new(string)
+	serverFlag        = new(string)
+	timeoutFlag       = new(time.Duration)
+	followFlag        = new(bool)
+	noColorFlag       = new(bool)
+	tableFlag         = new(bool)
+	widthFlag         = new(int)
+	jprsTypeFlag      = new(string)
+	followHandlesFlag = new(bool)
+	compareFlag       = new(bool)
+	formatFlag        = new(string)
+	templateFlag      = new(string)
+	outputFlag        = new(string)
+	fieldsFlag        = new(string)
+	fieldFlag         = new(string)
+	queryFlag         = new(string)
+	listFileFlag      = new(string)
+	verboseFlag       = new(bool)
+	langFlag          = new(string)
+	tzFlag            = new(string)
+	dateFormatFlag    = new(string)
+	relativeFlag      = new(bool)
+	cacheTTLFlag      = new(string)
+	htmlFlag          = new(string)
+	addrFlag          = new(string)
+	configFlag        = new(string)
+	forceFlag         = new(bool)
+	profileFlag       = new(string)
+	interactiveFlag   = new(bool)
+	noPagerFlag       = new(bool)
+	themeFlag         = new(string)
+	borderFlag        = new(string)
+	ambiguousFlag     = new(string)
+	unicodeFlag       = new(bool)
+	punycodeFlag      = new(bool)
+	kindsFlag         = new(string)
+	tldsFlag          = new(string)
+	dryRunFlag        = new(bool)
+	allFlag           = new(bool)
+)
 
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
@@ -106,91 +140,118 @@ func hardWrap(s string, width int) []string {
 	return out
 }
 
-func renderTable(title string, kvs []KV, width int, color bool) []string {
-	if width < 40 {
-		width = 40
-	}
-
-	maxKey := 0
+func columnWidths(kvs []KV, width int) (maxKey, valueWidth int) {
 	for _, kv := range kvs {
 		if w := dispWidth(kv.Key); w > maxKey {
 			maxKey = w
 		}
 	}
-	innerWidth := width - 2
-	valueWidth := innerWidth - 2 - maxKey - 3
+	// 左右の枠と余白、キーと値の区切りを除いた幅
+	b := tableBorder
+	innerWidth := width - dispWidth(b.left()) - dispWidth(b.right()) - dispWidth(b.sep)
+	valueWidth = innerWidth - maxKey
 	if valueWidth < 16 {
 		valueWidth = 16
-		maxKey = innerWidth - 2 - 3 - valueWidth
+		maxKey = innerWidth - valueWidth
 		if maxKey < 8 {
 			maxKey = 8
 		}
 	}
+	return maxKey, valueWidth
+}
 
-	top := "┏" + strings.Repeat("━", width-2) + "┓"
-	mid := "┣" + strings.Repeat("━", width-2) + "┫"
-	bot := "┗" + strings.Repeat("━", width-2) + "┛"
-
-	tspace := width - 2 - dispWidth(title)
-	if tspace < 0 {
-		tspace = 0
-	}
-	l := tspace / 2
-	r := tspace - l
-	titleLine := "┃" + strings.Repeat(" ", l) + title + strings.Repeat(" ", r) + "┃"
-
-	out := []string{
-		colorize(top, "title", color),
-		colorize(titleLine, "title", color),
-		colorize(mid, "title", color),
-	}
-
+func renderKVRows(kvs []KV, maxKey, valueWidth int, color bool) []string {
+	b := tableBorder
+	var out []string
 	for _, kv := range kvs {
 		keyCell := padRightByWidth(kv.Key, maxKey)
-		wrapped := wrapByWidth(kv.Val, valueWidth)
+		var wrapped []string
+		for _, v := range strings.Split(kv.Val, "\n") {
+			wrapped = append(wrapped, wrapByWidth(v, valueWidth)...)
+		}
+		role := "value"
+		if kv.Role != "" {
+			role = kv.Role
+		}
 		for i, w := range wrapped {
+			key := strings.Repeat(" ", maxKey)
 			if i == 0 {
-				line := "┃ " +
-					colorize(keyCell, "label", color) +
-					" : " +
-					colorize(w, "value", color) +
-					" ┃"
-				out = append(out, line)
-			} else {
-				line := "┃ " +
-					strings.Repeat(" ", maxKey) +
-					" : " +
-					colorize(w, "value", color) +
-					" ┃"
-				out = append(out, line)
+				key = colorize(keyCell, "label", color)
+			}
+			// 枠のない compact では値の右側を空白で埋めない
+			if b.v == "" {
+				w = strings.TrimRight(w, " ")
 			}
+			out = append(out, b.left()+key+b.sep+colorize(w, role, color)+b.right())
 		}
 	}
-	out = append(out, colorize(bot, "title", color))
 	return out
 }
 
-type KV struct{ Key, Val string }
+func renderTable(title string, kvs []KV, width int, color bool) []string {
+	width = fitTableWidth(title, kvs, width)
+	if width < 40 {
+		width = 40
+	}
+	maxKey, valueWidth := columnWidths(kvs, width)
+
+	var out []string
+	for _, l := range tableBorder.rule(tableBorder.tl, tableBorder.tr, width) {
+		out = append(out, colorize(l, "title", color))
+	}
+	out = append(out, colorize(tableBorder.title(title, width), "title", color))
+	for _, l := range tableBorder.rule(tableBorder.lt, tableBorder.rt, width) {
+		out = append(out, colorize(l, "title", color))
+	}
+	out = append(out, renderKVRows(kvs, maxKey, valueWidth, color)...)
+	for _, l := range tableBorder.rule(tableBorder.bl, tableBorder.br, width) {
+		out = append(out, colorize(l, "title", color))
+	}
+	return out
+}
 
-type Config struct {
-	Lang          string `json:"lang"`
-	DefaultOutput string `json:"default_output"`
-	Color         bool   `json:"color"`
+type KV struct {
+	Key, Val string
+	Role     string // 値の色の役割（status-risk / status-ok など、空なら value）
 }
 
 var jprsKeys = map[string]string{
-	"ドメイン名":  "Domain Name",
-	"登録者名":   "Registrant",
-	"登録年月日":  "Creation Date",
-	"有効期限":   "Registry Expiry Date",
-	"最終更新":   "Updated Date",
-	"状態":     "Status",
-	"公開連絡窓口": "Registrant Contact",
-	"名前":     "Name",
-	"郵便番号":   "Postal Code",
-	"住所":     "Postal Address",
-	"電話番号":   "Phone",
-	"FAX番号":  "Fax",
+	"ドメイン名":           "Domain Name",
+	"登録者名":            "Registrant",
+	"登録年月日":           "Creation Date",
+	"接続年月日":           "Connected Date",
+	"有効期限":            "Registry Expiry Date",
+	"最終更新":            "Updated Date",
+	"状態":              "Status",
+	"公開連絡窓口":          "Registrant Contact",
+	"名前":              "Name",
+	"郵便番号":            "Postal Code",
+	"住所":              "Postal Address",
+	"電話番号":            "Phone",
+	"FAX番号":           "Fax",
+	"組織名":             "Organization",
+	"そしきめい":           "Organization (Kana)",
+	"組織種別":            "Organization Type",
+	"登録担当者":           "Administrative Contact",
+	"技術連絡担当者":         "Technical Contact",
+	"ネームサーバ":          "Name Server",
+	"署名鍵":             "Signing Key",
+	"JPNICハンドル":       "JPNIC Handle",
+	"電子メール":           "Email",
+	"部署":              "Division",
+	"肩書":              "Title",
+	"通知アドレス":          "Reply Mail",
+	"last, first":     "Last, First",
+	"ホスト名":            "Host Name",
+	"IPアドレス":          "IP Address",
+	"Registered Date": "Creation Date",
+	"Created on":      "Creation Date",
+	"Expires on":      "Registry Expiry Date",
+	"Last Update":     "Updated Date",
+	"Last Updated":    "Updated Date",
+	"State":           "Status",
+	"E-Mail":          "Email",
+	"Postal code":     "Postal Code",
 }
 
 func extractKVs(raw, lang string) []KV {
@@ -204,28 +265,23 @@ func extractKVs(raw, lang string) []KV {
 			continue
 		}
 
-		if strings.HasPrefix(l, "[") && strings.Contains(l, "]") {
-			right := strings.TrimPrefix(l, "[")
-			parts := strings.SplitN(right, "]", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				val := strings.TrimSpace(parts[1])
-				if val == "" && i+1 < len(lines) {
-					next := strings.TrimSpace(strings.TrimRight(lines[i+1], "\r"))
-					if next != "" && !strings.HasPrefix(next, "[") {
-						val = next
-						i++
-					}
-				}
-				if en, ok := jprsKeys[key]; ok {
-					key = en
-				}
-				keyLabel := translateLabel(key, lang)
-				if val != "" && !seen[keyLabel+":"+val] {
-					kvs = append(kvs, KV{Key: keyLabel, Val: val})
-					seen[keyLabel+":"+val] = true
+		if key, val, ok := parseJPRSLine(l); ok {
+			if val == "" && i+1 < len(lines) {
+				next := strings.TrimSpace(strings.TrimRight(lines[i+1], "\r"))
+				if next != "" && jprsLineRe.FindStringSubmatch(next) == nil && !strings.HasSuffix(next, ":") {
+					val = next
+					i++
 				}
 			}
+			if classifyField(key) == "dates" {
+				val = displayDateIn(val, jstZone)
+			}
+			val = displayIDN(val)
+			keyLabel := translateLabel(key, lang)
+			if val != "" && !seen[keyLabel+":"+val] {
+				kvs = append(kvs, KV{Key: keyLabel, Val: val})
+				seen[keyLabel+":"+val] = true
+			}
 			continue
 		}
 
@@ -241,28 +297,11 @@ func extractKVs(raw, lang string) []KV {
 				continue
 			}
 
-			keyLower := strings.ToLower(key)
-			isKnownKey := false
-
-			if _, ok := labels[key]; ok {
-				isKnownKey = true
-			}
-
-			commonPatterns := []string{
-				"domain", "registrar", "registrant", "admin", "tech", "billing",
-				"created", "updated", "expires", "expiry", "status", "server", "name",
-				"organization", "organisation", "email", "phone", "fax", "address",
-				"city", "state", "country", "postal", "whois", "url", "iana",
-			}
-
-			for _, pattern := range commonPatterns {
-				if strings.Contains(keyLower, pattern) {
-					isKnownKey = true
-					break
+			if isKnownKey(key) {
+				if classifyField(key) == "dates" {
+					val = displayDate(val)
 				}
-			}
-
-			if isKnownKey {
+				val = displayIDN(val)
 				keyLabel := translateLabel(key, lang)
 				if !seen[keyLabel+":"+val] {
 					kvs = append(kvs, KV{Key: keyLabel, Val: val})
@@ -274,62 +313,39 @@ func extractKVs(raw, lang string) []KV {
 	return kvs
 }
 
-func loadConfig(path string) Config {
-	file, err := os.Open(path)
-	if err != nil {
-		return Config{Lang: "en", DefaultOutput: "conventional", Color: true}
+func localeSearchDirs(config Config) []string {
+	dirs := []string{"locales"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "whois", "locales"))
 	}
-	defer file.Close()
-
-	var config Config
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
-		return Config{Lang: "en", DefaultOutput: "conventional", Color: true}
+	if config.LocalesDir != "" {
+		dirs = append(dirs, config.LocalesDir)
 	}
-	return config
+	return dirs
 }
 
-var labels = map[string]string{
-	"Registrar":                     "レジストラ",
-	"Registrar WHOIS Server":        "レジストラWhoisサーバ",
-	"Registrar URL":                 "レジストラURL",
-	"Creation Date":                 "登録日",
-	"Registry Expiry Date":          "有効期限",
-	"Name Server":                   "ネームサーバ",
-	"Registrar IANA ID":             "IANA ID",
-	"Registrar Abuse Contact Email": "不正通報先メール",
-	"Registrar Abuse Contact Phone": "不正通報先電話",
+// conventional 出力で整形表示するラベル
+var prettyKeys = []string{
+	"Registrar",
+	"Registrar WHOIS Server",
+	"Registrar URL",
+	"Creation Date",
+	"Registry Expiry Date",
+	"Name Server",
+	"Registrar IANA ID",
+	"Registrar Abuse Contact Email",
+	"Registrar Abuse Contact Phone",
+	"Organization Type",
+	"Administrative Contact",
+	"Technical Contact",
 }
 
-func translateLabel(label, lang string) string {
-	if lang == "ja" {
-		if ja, ok := labels[label]; ok {
-			return ja
-		}
-	}
-	return label
-}
-
-func colorize(s string, color string, enable bool) string {
+// colorize は役割（label / value / status-risk ...）に対応するテーマの色で s を囲む
+func colorize(s string, role string, enable bool) string {
 	if !enable {
 		return s
 	}
-	switch color {
-	case "label":
-		return "\033[1;34m" + s + "\033[0m" // 青太字
-	case "value":
-		return "\033[1;37m" + s + "\033[0m" // 白太字
-	case "title":
-		return "\033[1;32m" + s + "\033[0m" // 緑太字
-	case "version":
-		return "\033[1;36m" + s + "\033[0m" // シアン太字
-	case "copyright":
-		return "\033[0;33m" + s + "\033[0m" // 黄色
-	case "usage":
-		return "\033[1;35m" + s + "\033[0m" // マゼンタ太字
-	case "option":
-		return "\033[0;32m" + s + "\033[0m" // 緑
-	}
-	return s
+	return paint(s, activeTheme[role])
 }
 
 func isStdoutTTY() bool {
@@ -345,41 +361,42 @@ func envNoColor() bool {
 	return ok
 }
 
+// TLD ごとの WHOIS サーバ（whois servers で一覧表示）
+var whoisServers = []struct{ suffix, server string }{
+	{".jp", "whois.jprs.jp:43"},
+	{".com", "whois.verisign-grs.com:43"},
+	{".net", "whois.verisign-grs.com:43"},
+	{".org", "whois.pir.org:43"},
+	{".info", "whois.afilias.net:43"},
+	{".biz", "whois.neulevel.biz:43"},
+	{".us", "whois.nic.us:43"},
+	{".co", "whois.nic.co:43"},
+	{".io", "whois.nic.io:43"},
+	{".dev", "whois.nic.google:43"},
+	{".xyz", "whois.nic.xyz:43"},
+	{".me", "whois.nic.me:43"},
+	{".top", "whois.nic.top:43"},
+	{".su", "whois.tcinet.ru:43"},
+	{".moe", "whois.nic.moe:43"},
+}
+
+const defaultWhoisServer = "whois.iana.org:43"
+
 func getWhoisServer(domain string) string {
 	domain = strings.ToLower(domain)
-
-	switch {
-	case strings.HasSuffix(domain, ".jp"):
-		return "whois.jprs.jp:43"
-	case strings.HasSuffix(domain, ".com"), strings.HasSuffix(domain, ".net"):
-		return "whois.verisign-grs.com:43"
-	case strings.HasSuffix(domain, ".org"):
-		return "whois.pir.org:43"
-	case strings.HasSuffix(domain, ".info"):
-		return "whois.afilias.net:43"
-	case strings.HasSuffix(domain, ".biz"):
-		return "whois.neulevel.biz:43"
-	case strings.HasSuffix(domain, ".us"):
-		return "whois.nic.us:43"
-	case strings.HasSuffix(domain, ".co"):
-		return "whois.nic.co:43"
-	case strings.HasSuffix(domain, ".io"):
-		return "whois.nic.io:43"
-	case strings.HasSuffix(domain, ".dev"):
-		return "whois.nic.google:43"
-	case strings.HasSuffix(domain, ".xyz"):
-		return "whois.nic.xyz:43"
-	case strings.HasSuffix(domain, ".me"):
-		return "whois.nic.me:43"
-	case strings.HasSuffix(domain, ".top"):
-		return "whois.nic.top:43"
-	case strings.HasSuffix(domain, ".su"):
-		return "whois.tcinet.ru:43"
-	case strings.HasSuffix(domain, ".moe"):
-		return "whois.nic.moe:43"
-	default:
-		return "whois.iana.org:43"
+	if sc, ok := matchServerOverride(domain); ok && sc.Host != "" {
+		return normalizeServer(sc.Host)
+	}
+	for _, s := range whoisServers {
+		if strings.HasSuffix(domain, s.suffix) {
+			return s.server
+		}
 	}
+	// squat・sweep が IANA から調べておいた TLD は直接問い合わせる
+	if s, ok := referredServer(domain); ok && s != "" {
+		return normalizeServer(s)
+	}
+	return defaultWhoisServer
 }
 
 func normalizeServer(s string) string {
@@ -392,8 +409,12 @@ func normalizeServer(s string) string {
 	return s + ":43"
 }
 
-func queryWhois(server, query string, timeout time.Duration) (string, error) {
+func queryWhois(server, query, charset string, timeout time.Duration, refresh bool) (string, error) {
 	addr := normalizeServer(server)
+	if raw, ok := cacheGet(addr, query); ok && !refresh {
+		return raw, nil
+	}
+	waitRateLimit(addr)
 	conn, err := net.DialTimeout("tcp", addr, timeout)
 	if err != nil {
 		return "", err
@@ -407,11 +428,19 @@ func queryWhois(server, query string, timeout time.Duration) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return string(b), nil
+	raw := string(b)
+	if charset != "" {
+		if raw, err = decodeCharset(b, charset); err != nil {
+			return "", err
+		}
+	}
+	cachePut(addr, query, raw)
+	return raw, nil
 }
 
 func extractReferral(raw string) string {
-	keys := []string{"Registrar WHOIS Server:", "Whois Server:", "WHOIS Server:", "ReferralServer:"}
+	// "refer:" は IANA が AS 番号・TLD の委任先として返す
+	keys := []string{"Registrar WHOIS Server:", "Whois Server:", "WHOIS Server:", "ReferralServer:", "refer:"}
 	for _, line := range strings.Split(raw, "\n") {
 		l := strings.TrimSpace(strings.TrimRight(line, "\r"))
 		if l == "" {
@@ -446,6 +475,93 @@ func extractReferral(raw string) string {
 	return ""
 }
 
+type lookupOptions struct {
+	Server        string
+	Timeout       time.Duration
+	Follow        bool
+	Lang          string
+	JPRSType      string
+	FollowHandles bool
+	Refresh       bool // 保存済みの応答を使わずに問い合わせる（取得した応答は保存する）
+}
+
+func lookup(domain string, opts lookupOptions) (*Record, error) {
+	// WHOIS サーバー決定（オーバーライド可能）
+	server := opts.Server
+	if server == "" {
+		switch {
+		case opts.JPRSType != "":
+			server = jprsServer
+		case net.ParseIP(domain) != nil:
+			server = "whois.arin.net:43"
+		default:
+			server = getWhoisServer(domain)
+			// IANA が WHOIS サーバを示さなかった TLD は、問い合わせても TLD の情報しか返らない
+			if s, ok := referredServer(domain); ok && s == "" && server == defaultWhoisServer {
+				return nil, errors.New(msg("err.no_tld_server", domain[strings.LastIndex(domain, ".")+1:]))
+			}
+		}
+	}
+
+	// 送信クエリ（config の servers のクエリ書式、JPRS検索タイプ・英語出力指定に対応）
+	settings := serverSettings(server, domain)
+	query := domain
+	if isASN(domain) {
+		// AS 番号は IANA から各 RIR へ参照をたどる（RIR は "AS15169" の形を受け付ける）
+		query = strings.ToUpper(domain)
+	}
+	if settings.Query != "" && opts.JPRSType == "" {
+		query = formatQuery(settings.Query, domain)
+	} else if isJPRSServer(server) && (opts.JPRSType != "" || strings.HasSuffix(domain, ".jp")) {
+		q, err := jprsQuery(opts.JPRSType, domain, opts.Lang)
+		if err != nil {
+			return nil, err
+		}
+		query = q
+	}
+
+	// 1回目のクエリ
+	raw1, err := queryWhois(server, query, settings.Charset, opts.Timeout, opts.Refresh)
+	if err != nil {
+		return nil, err
+	}
+	chain := []Hop{{Server: server, Query: query, Raw: raw1}}
+	finalRaw := raw1
+	var referralErr error
+
+	// リファラ追跡（例: .com/.net でレジストラ側へ）
+	if opts.Follow {
+		if ref := extractReferral(raw1); ref != "" {
+			if !strings.EqualFold(normalizeServer(ref), normalizeServer(server)) {
+				refQuery := domain
+				if isASN(domain) {
+					refQuery = strings.ToUpper(domain)
+				}
+				refSettings := referralSettings(ref, server, domain)
+				if refSettings.Query != "" {
+					refQuery = formatQuery(refSettings.Query, domain)
+				}
+				raw2, err := queryWhois(ref, refQuery, refSettings.Charset, opts.Timeout, opts.Refresh)
+				if err != nil {
+					referralErr = fmt.Errorf("%s: %w", normalizeServer(ref), err)
+				} else if raw2 != "" {
+					chain = append(chain, Hop{Server: normalizeServer(ref), Query: refQuery, Raw: raw2})
+					finalRaw = raw2
+				}
+			}
+		}
+	}
+
+	rec := parseRecord(finalRaw)
+	rec.Query = domain
+	rec.Chain = chain
+	rec.ReferralErr = referralErr
+	if opts.FollowHandles && (isJPRSServer(server) || isJPRSResponse(finalRaw)) {
+		followJPRSHandles(rec, server, opts)
+	}
+	return rec, nil
+}
+
 func formatPretty(raw string, lang string, color bool) []string {
 	lines := strings.Split(raw, "\n")
 	out := []string{}
@@ -455,12 +571,17 @@ func formatPretty(raw string, lang string, color bool) []string {
 		if l == "" {
 			continue
 		}
-		for key := range labels {
+		for _, key := range prettyKeys {
 			if strings.Contains(l, key) {
 				parts := strings.SplitN(l, ":", 2)
 				if len(parts) == 2 {
-					label := translateLabel(strings.TrimSpace(parts[0]), lang)
+					key := strings.TrimSpace(parts[0])
+					label := translateLabel(key, lang)
 					value := strings.TrimSpace(parts[1])
+					if classifyField(key) == "dates" {
+						value = displayDate(value)
+					}
+					value = displayIDN(value)
 					formatted := fmt.Sprintf("%s: %s",
 						colorize(label, "label", color),
 						colorize(value, "value", color))
@@ -484,10 +605,10 @@ func output(lines []string, filename string) {
 	if filename != "" {
 		err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")+"\n"), 0644)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "Failed to write to file:", err)
+			fmt.Fprintln(os.Stderr, msg("err.write", err))
 			os.Exit(1)
 		}
-	} else {
+	} else if !pageLines(lines) {
 		for _, line := range lines {
 			fmt.Println(line)
 		}
@@ -496,7 +617,7 @@ func output(lines []string, filename string) {
 
 func centerLine(leftBorder, text, rightBorder string, totalWidth int, colorLeft, colorText, colorRight string, enableColor bool) string {
 	visibleLen := dispWidth(text)
-	spaceTotal := totalWidth - visibleLen - 2
+	spaceTotal := max(totalWidth-visibleLen-dispWidth(leftBorder)-dispWidth(rightBorder), 0)
 	leftSpaces := spaceTotal / 2
 	rightSpaces := spaceTotal - leftSpaces
 	return colorize(leftBorder+strings.Repeat(" ", leftSpaces), colorLeft, enableColor) +
@@ -504,154 +625,311 @@ func centerLine(leftBorder, text, rightBorder string, totalWidth int, colorLeft,
 		colorize(strings.Repeat(" ", rightSpaces)+rightBorder, colorLeft, enableColor)
 }
 
-func main() {
-	flag.Parse()
-	args := flag.Args()
+// printBanner は version / help の見出しを -border の罫線で囲んで表示する
+func printBanner(title, role string, enableColor bool) {
+	b := tableBorder
+	for _, l := range b.rule(b.tl, b.tr, 79) {
+		fmt.Println(colorize(l, "title", enableColor))
+	}
+	fmt.Println(centerLine(b.v, title, b.v, 79, "title", role, "title", enableColor))
+	for _, l := range b.rule(b.bl, b.br, 79) {
+		fmt.Println(colorize(l, "title", enableColor))
+	}
+}
 
-	if *versionFlag {
-		// カラー出力（NO_COLOR/非TTYなら無効化）
-		enableColor := !*noColorFlag && isStdoutTTY() && !envNoColor()
-		fmt.Println(colorize("┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓", "title", enableColor))
-		fmt.Println(centerLine("┃", "Whois CLI App", "┃", 79, "title", "version", "title", enableColor))
-		fmt.Println(colorize("┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛", "title", enableColor))
-		fmt.Println()
-		fmt.Printf("%s %s\n",
-			colorize("Version:", "label", enableColor),
-			colorize("v"+Version, "version", enableColor))
-		fmt.Printf("%s %s\n",
-			colorize("Description:", "label", enableColor),
-			colorize("A simple command-line whois client with IDN support", "value", enableColor))
-		fmt.Printf("%s %s\n",
-			colorize("License:", "label", enableColor),
-			colorize("BSD 2-Clause License", "value", enableColor))
-		fmt.Printf("%s %s\n",
-			colorize("Copyright:", "label", enableColor),
-			colorize("(c) 2025 darui3018823, All rights reserved.", "copyright", enableColor))
-		return
-	}
-
-	if *helpFlag {
-		enableColor := !*noColorFlag && isStdoutTTY() && !envNoColor()
-		fmt.Println(colorize("┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓", "title", enableColor))
-		fmt.Println(colorize("┃                            ", "title", enableColor) + colorize("Whois CLI Help", "usage", enableColor) + colorize("                             ┃", "title", enableColor))
-		fmt.Println(colorize("┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛", "title", enableColor))
-		fmt.Println()
-		fmt.Printf("%s %s\n",
-			colorize("Usage:", "label", enableColor),
-			colorize("whois [options] <domain>", "usage", enableColor))
+func printVersion(enableColor bool) {
+	printBanner(msg("version.title"), "version", enableColor)
+	fmt.Println()
+	fmt.Printf("%s %s\n",
+		colorize(msg("version.version"), "label", enableColor),
+		colorize("v"+Version, "version", enableColor))
+	fmt.Printf("%s %s\n",
+		colorize(msg("version.description"), "label", enableColor),
+		colorize(msg("version.description_text"), "value", enableColor))
+	fmt.Printf("%s %s\n",
+		colorize(msg("version.license"), "label", enableColor),
+		colorize("BSD 2-Clause License", "value", enableColor))
+	fmt.Printf("%s %s\n",
+		colorize(msg("version.copyright"), "label", enableColor),
+		colorize("(c) 2025 darui3018823, All rights reserved.", "copyright", enableColor))
+}
+
+// printHelp はコマンドの FlagSet からオプション一覧を組み立てて表示する
+func printHelp(cmd *command, enableColor bool) {
+	printBanner(msg("help.title"), "usage", enableColor)
+	fmt.Println()
+	usage := cmd.usage
+	if cmd.name == defaultCommand {
+		usage = msg("help.usage_text")
+	}
+	fmt.Printf("%s %s\n",
+		colorize(msg("help.usage"), "label", enableColor),
+		colorize(usage, "usage", enableColor))
+	if cmd.name != defaultCommand {
+		fmt.Printf("  %s\n", colorize(msg("cmd."+cmd.name), "value", enableColor))
+	}
+
+	// 既定コマンド（lookup）のヘルプには他のサブコマンドも並べる
+	if cmd.name == defaultCommand {
 		fmt.Println()
-		fmt.Printf("%s\n", colorize("Options:", "label", enableColor))
-
-		options := []struct {
-			flag string
-			desc string
-		}{
-			{"-raw", "Output raw whois text without formatting"},
-			{"-table", "Render output as a box-drawn table"},
-			{"-width <n>", "Table width (columns) when using -table"},
-			{"-o <file>", "Output to file (automatically disables colors)"},
-			{"-server <host[:port]>", "Override WHOIS server (e.g., whois.verisign-grs.com:43)"},
-			{"-timeout <duration>", "Network timeout (e.g., 5s, 2m)"},
-			{"-follow", "Follow referral WHOIS server if present (default: true)"},
-			{"-nocolor", "Disable colored output"},
-			{"-version", "Show version information"},
-			{"-help", "Show this help message"},
-		}
-
-		for _, opt := range options {
+		fmt.Printf("%s\n", colorize(msg("help.commands"), "label", enableColor))
+		for _, c := range commands {
 			fmt.Printf("  %s  %s\n",
-				colorize(fmt.Sprintf("%-25s", opt.flag), "option", enableColor),
-				colorize(opt.desc, "value", enableColor))
+				colorize(fmt.Sprintf("%-25s", c.name), "option", enableColor),
+				colorize(msg("cmd."+c.name), "value", enableColor))
 		}
+	}
 
+	fs := cmd.flagSet()
+	fmt.Println()
+	fmt.Printf("%s\n", colorize(msg("help.options"), "label", enableColor))
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "-" + f.Name
+		if arg := flagArgs[f.Name]; arg != "" {
+			name += " " + arg
+		}
+		desc := f.Usage
+		if id := "opt." + strings.ReplaceAll(f.Name, "-", "_"); hasMsg(id) {
+			desc = msg(id)
+		}
+		fmt.Printf("  %s  %s\n",
+			colorize(fmt.Sprintf("%-25s", name), "option", enableColor),
+			colorize(desc, "value", enableColor))
+	})
+	fmt.Printf("  %s  %s\n",
+		colorize(fmt.Sprintf("%-25s", "-help"), "option", enableColor),
+		colorize(msg("opt.help"), "value", enableColor))
+
+	if len(cmd.examples) > 0 {
 		fmt.Println()
-		fmt.Printf("%s\n", colorize("Examples:", "label", enableColor))
-		examples := []string{
-			"whois daruks.com",
-			"whois -table minecraft.net",
-			"whois -raw example.org",
-			"whois -o ./output.txt wikipedia.org",
-			"whois -server whois.verisign-grs.com:43 daruks.com",
-			"whois アググン.jp",
-		}
-		for _, ex := range examples {
+		fmt.Printf("%s\n", colorize(msg("help.examples"), "label", enableColor))
+		for _, ex := range cmd.examples {
 			fmt.Printf("  %s\n", colorize(ex, "usage", enableColor))
 		}
+	}
 
-		fmt.Println()
-		fmt.Printf("%s %s\n",
-			colorize("Config file:", "label", enableColor),
-			colorize("config.json (lang, default_output, color)", "value", enableColor))
-		return
+	fmt.Println()
+	fmt.Printf("%s %s\n",
+		colorize(msg("help.config"), "label", enableColor),
+		colorize(msg("help.config_text"), "value", enableColor))
+}
+
+// outputMode は -output（省略時は fallback）を基に、-q / -field / -fields の指定を反映した出力形式を返す
+func outputMode(fallback string) string {
+	mode := strings.ToLower(*outputFlag)
+	if mode == "" {
+		mode = strings.ToLower(fallback)
+	}
+	// -q / -field / -fields は整形せずに値だけを取り出す
+	switch {
+	case *queryFlag != "":
+		mode = "query"
+	case *fieldFlag != "" || (flagWasSet("fields") && mode != "csv" && mode != "tsv"):
+		mode = "fields"
 	}
+	return mode
+}
 
-	if len(args) != 1 {
-		fmt.Println("Usage: whois [options] <domain>")
-		flag.PrintDefaults()
-		return
+func flagWasSet(name string) bool {
+	set := false
+	if activeFlags == nil {
+		return false
 	}
+	activeFlags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-	fmt.Println("Whois_CLIApp (c) 2025 darui3018823, All rights reserved.")
-	fmt.Println()
+var asnRe = regexp.MustCompile(`^as\d+$`)
 
-	inputDomain := args[0]
-	asciiDomain, errIDN := idna.Lookup.ToASCII(strings.TrimSpace(inputDomain))
-	domain := inputDomain
-	if errIDN == nil && asciiDomain != "" {
-		domain = asciiDomain
-	}
-	domain = strings.ToLower(domain)
+// isASN は "as15169" のような AS 番号かを返す（normalizeDomain で小文字化済み）
+func isASN(name string) bool {
+	return asnRe.MatchString(name)
+}
 
-	config := loadConfig("config.json")
+func flagLookupOptions(config Config) lookupOptions {
+	return lookupOptions{
+		Server:        *serverFlag,
+		Timeout:       *timeoutFlag,
+		Follow:        *followFlag,
+		Lang:          config.Lang,
+		JPRSType:      *jprsTypeFlag,
+		FollowHandles: *followHandlesFlag,
+	}
+}
 
-	if *noColorFlag {
-		config.Color = false
+// tableWidth は -width、$COLUMNS、端末の幅、120 の順に表の幅を決める
+func tableWidth() int {
+	width := *widthFlag
+	if width <= 0 {
+		if c := os.Getenv("COLUMNS"); c != "" {
+			if n, err := strconv.Atoi(c); err == nil && n >= 40 {
+				width = n
+			}
+		}
 	}
+	if width <= 0 {
+		if w, _, ok := terminalSize(); ok && w >= 40 {
+			width = w
+		}
+	}
+	if width <= 0 {
+		width = 120
+	}
+	return width
+}
 
-	if *outFile != "" {
-		config.Color = false
+// セクション分けした表を優先し、分類できない応答は従来の一覧表にする
+func recordTable(rec *Record, config Config) []string {
+	return recordTableWidth(rec, config, tableWidth())
+}
+
+func recordTableWidth(rec *Record, config Config, width int) []string {
+	if sections := buildSections(rec, config.Lang, *verboseFlag); len(sections) > 0 {
+		return renderSectionTable(msg("table.title"), sections, width, config.Color)
 	}
-	// Respect NO_COLOR and non-TTY stdout
-	if envNoColor() || !isStdoutTTY() {
-		config.Color = false
+	kvs := append(extractKVs(rec.Raw, config.Lang), contactKVs(rec, config.Lang)...)
+	if len(kvs) > 0 {
+		kvs = append(lookalikeKVs(rec.Query), kvs...)
+	}
+	if len(kvs) == 0 {
+		return nil
 	}
+	return renderTable(msg("table.title"), kvs, width, config.Color)
+}
 
-	// WHOIS サーバー決定（オーバーライド可能）
-	server := *serverFlag
-	if server == "" {
-		if net.ParseIP(domain) != nil {
-			server = "whois.arin.net:43"
-		} else {
-			server = getWhoisServer(domain)
+func rawLines(raw string) []string {
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// renderRecord は raw / json / table / conventional のいずれかで rec を整形する。
+// 表に分類できない応答は conventional にフォールバックする
+func renderRecord(rec *Record, mode string, config Config) ([]string, error) {
+	switch mode {
+	case "raw":
+		return rawLines(rec.Raw), nil
+	case "json":
+		return printJSON(rec)
+	case "table":
+		if lines := recordTable(rec, config); len(lines) > 0 {
+			return lines, nil
+		}
+	}
+	var lines []string
+	for _, kv := range lookalikeKVs(rec.Query) {
+		key := kv.Key
+		if key == "" {
+			key = msg("confusable.label")
 		}
+		lines = append(lines, fmt.Sprintf("%s: %s",
+			colorize(key, "label", config.Color),
+			colorize(kv.Val, kv.Role, config.Color)))
+	}
+	lines = append(lines, formatPretty(rec.Raw, config.Lang, config.Color)...)
+	key := ""
+	for _, kv := range statusKVs(rec, config.Lang) {
+		if kv.Key != "" {
+			key = kv.Key
+		}
+		role := "value"
+		if kv.Role != "" {
+			role = kv.Role
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s",
+			colorize(key, "label", config.Color),
+			colorize(kv.Val, role, config.Color)))
 	}
+	for _, kv := range contactKVs(rec, config.Lang) {
+		lines = append(lines, fmt.Sprintf("%s: %s",
+			colorize(kv.Key, "label", config.Color),
+			colorize(kv.Val, "value", config.Color)))
+	}
+	return lines, nil
+}
 
-	// 送信クエリ（JPRS英語出力指定に対応）
-	query := domain
-	if strings.HasSuffix(domain, ".jp") && strings.Contains(strings.ToLower(server), "jprs.jp") {
-		if strings.ToLower(config.Lang) == "en" {
-			query = domain + "/e"
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// runLookup は既定コマンド。1件の検索、-compare、エクスポート形式での一括出力を扱う
+func runLookup(args []string, config Config) int {
+	if *versionFlag {
+		printVersion(config.Color)
+		return exitOK
+	}
+	if *interactiveFlag {
+		return runREPL(config)
+	}
+
+	mode := outputMode(config.DefaultOutput)
+	bulk := (exportFormats[mode] || mode == "query" || mode == "fields") && !*rawFlag && !*tableFlag
+
+	if (len(args) == 0 && !(bulk && *listFileFlag != "")) || (len(args) > 1 && !*compareFlag && !bulk) {
+		fmt.Fprintln(os.Stderr, msg("err.usage"))
+		fmt.Fprintln(os.Stderr, msg("err.usage_hint"))
+		return exitUsage
+	}
+
+	// テンプレート・エクスポート出力は他コマンドへ渡すことが多いのでバナーを出さない
+	useTemplate := *formatFlag != "" || *templateFlag != ""
+	if !useTemplate && !bulk && (mode != "json" || *rawFlag || *tableFlag) {
+		fmt.Println(msg("banner"))
+		fmt.Println()
+	}
+
+	if *compareFlag {
+		output(runCompare(args, config), *outFile)
+		return exitOK
+	}
+
+	if bulk {
+		failed, err := runExport(mode, args, config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.export", err))
+			return exitError
+		}
+		if failed > 0 {
+			return exitError
 		}
+		return exitOK
 	}
 
-	// 1回目のクエリ
-	raw1, err := queryWhois(server, query, *timeoutFlag)
+	domain, err := normalizeDomain(args[0])
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error connecting to whois server:", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
 	}
+	rec, err := lookup(domain, flagLookupOptions(config))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.connect", err))
+		return exitError
+	}
+	if rec.ReferralErr != nil {
+		fmt.Fprintln(os.Stderr, msg("lookup.referral_failed", rec.ReferralErr))
+	}
+	finalRaw := rec.Raw
 
-	finalRaw := raw1
-
-	// リファラ追跡（例: .com/.net でレジストラ側へ）
-	if *followFlag {
-		if ref := extractReferral(raw1); ref != "" {
-			if !strings.EqualFold(normalizeServer(ref), normalizeServer(server)) {
-				if raw2, err := queryWhois(ref, domain, *timeoutFlag); err == nil && raw2 != "" {
-					finalRaw = raw2
-				}
-			}
+	if useTemplate {
+		t, err := loadTemplate(*formatFlag, *templateFlag, config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.template", err))
+			return exitError
+		}
+		text, err := renderTemplate(t, rec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.template", err))
+			return exitError
 		}
+		output(strings.Split(text, "\n"), *outFile)
+		return exitOK
 	}
 
 	if *outFile != "" {
@@ -659,69 +937,23 @@ func main() {
 	}
 
 	if *rawFlag {
-		scanner := bufio.NewScanner(strings.NewReader(finalRaw))
-		var lines []string
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
-		}
-		output(lines, *outFile)
-		return
+		output(rawLines(finalRaw), *outFile)
+		return exitOK
 	}
 
 	if *tableFlag {
-		width := *widthFlag
-		if width <= 0 {
-			if c := os.Getenv("COLUMNS"); c != "" {
-				if n, err := strconv.Atoi(c); err == nil && n >= 40 {
-					width = n
-				}
-			}
-			if width <= 0 {
-				width = 120
-			}
-		}
-		kvs := extractKVs(finalRaw, config.Lang)
-		if len(kvs) > 0 {
-			lines := renderTable("Whois Result", kvs, width, config.Color)
+		if lines := recordTable(rec, config); len(lines) > 0 {
 			output(lines, *outFile)
-			return
+			return exitOK
 		}
 	}
 
-	// フラグが指定されていない場合は設定ファイルに従う
-	switch strings.ToLower(config.DefaultOutput) {
-	case "raw":
-		scanner := bufio.NewScanner(strings.NewReader(finalRaw))
-		var lines []string
-		for scanner.Scan() {
-			lines = append(lines, scanner.Text())
-		}
-		output(lines, *outFile)
-		return
-	case "table":
-		kvs := extractKVs(finalRaw, config.Lang)
-		width := *widthFlag
-		if width <= 0 {
-			if c := os.Getenv("COLUMNS"); c != "" {
-				if n, err := strconv.Atoi(c); err == nil && n >= 40 {
-					width = n
-				}
-			}
-			if width <= 0 {
-				width = 120
-			}
-		}
-		if len(kvs) > 0 {
-			lines := renderTable("Whois Result", kvs, width, config.Color)
-			output(lines, *outFile)
-			return
-		}
-		// テーブル抽出失敗時はconventionalにフォールバック
-		fallthrough
-	case "conventional":
-		fallthrough
-	default:
-		lines := formatPretty(finalRaw, config.Lang, config.Color)
-		output(lines, *outFile)
+	// フラグが指定されていない場合は -output、次に設定ファイルに従う
+	lines, err := renderRecord(rec, mode, config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.export", err))
+		return exitError
 	}
+	output(lines, *outFile)
+	return exitOK
 }
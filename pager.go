@@ -0,0 +1,95 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// fitTables が true の間は表を内容の幅まで縮める（幅を端末から決めたときに prepare で設定する）
+var fitTables bool
+
+// terminalSize は標準出力の端末の幅と高さを返す（Linux / macOS は TIOCGWINSZ、Windows はコンソール API）
+func terminalSize() (width, height int, ok bool) {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 || h <= 0 {
+		return 0, 0, false
+	}
+	return w, h, true
+}
+
+// fitTableWidth は KV の表に必要な幅を返す（width より広くはしない）
+func fitTableWidth(title string, kvs []KV, width int) int {
+	if !fitTables {
+		return width
+	}
+	need := dispWidth(title) + 4
+	maxKey, maxVal := 0, 0
+	for _, kv := range kvs {
+		maxKey = max(maxKey, dispWidth(kv.Key))
+		for _, v := range strings.Split(kv.Val, "\n") {
+			maxVal = max(maxVal, dispWidth(strings.TrimSpace(v)))
+		}
+	}
+	// "┃ " + キー + " : " + 値 + " ┃"
+	need = max(need, maxKey+maxVal+7)
+	return min(width, max(need, 40))
+}
+
+// pagerCommand は $PAGER（空なら使わない）、未設定なら less -R を返す
+func pagerCommand() []string {
+	if p, ok := os.LookupEnv("PAGER"); ok {
+		return strings.Fields(p)
+	}
+	if _, err := exec.LookPath("less"); err == nil {
+		return []string{"less", "-R"}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"more"}
+	}
+	return nil
+}
+
+// displayRows は折り返しを含めた端末上の行数を返す
+func displayRows(lines []string, width int) int {
+	rows := 0
+	for _, l := range lines {
+		rows += max(1, (dispWidth(l)+width-1)/width)
+	}
+	return rows
+}
+
+// pageLines は端末の高さを超える出力をページャに渡す。表示しなかった場合は false を返す
+func pageLines(lines []string) bool {
+	if *noPagerFlag || !isStdoutTTY() {
+		return false
+	}
+	width, height, ok := terminalSize()
+	if !ok || displayRows(lines, width) < height {
+		return false
+	}
+	args := pagerCommand()
+	if len(args) == 0 {
+		return false
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	// less に -R を付けずに PAGER を設定している場合もカラーを表示できるようにする
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+	// q で途中で閉じた場合などの終了コードは無視する
+	_ = cmd.Wait()
+	return true
+}
@@ -0,0 +1,193 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// パス式の1ステップ（.key / [n] / []）
+type pathStep struct {
+	key   string
+	index int
+	iter  bool
+	isIdx bool
+}
+
+// parsePath は ".nameservers[]" や ".contacts[0].email" のような式を解釈する
+func parsePath(expr string) ([]pathStep, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, ".") {
+		return nil, errors.New(msg("err.query_start", expr))
+	}
+	var steps []pathStep
+	i := 0
+	for i < len(expr) {
+		switch expr[i] {
+		case '.':
+			i++
+			start := i
+			for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+				i++
+			}
+			if key := expr[start:i]; key != "" {
+				steps = append(steps, pathStep{key: key})
+			}
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, errors.New(msg("err.query_bracket", expr))
+			}
+			inner := strings.TrimSpace(expr[i+1 : i+end])
+			if inner == "" {
+				steps = append(steps, pathStep{iter: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, errors.New(msg("err.query_index", inner, expr))
+				}
+				steps = append(steps, pathStep{index: n, isIdx: true})
+			}
+			i += end + 1
+		default:
+			return nil, errors.New(msg("err.query_unexpected", expr[i], i, expr))
+		}
+	}
+	return steps, nil
+}
+
+func evalPath(v any, steps []pathStep) []any {
+	cur := []any{v}
+	for _, st := range steps {
+		var next []any
+		for _, c := range cur {
+			switch {
+			case st.iter:
+				switch t := c.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					// 実行ごとに順序が変わらないようキー順に並べる
+					for _, k := range sortedKeys(t) {
+						next = append(next, t[k])
+					}
+				}
+			case st.isIdx:
+				if arr, ok := c.([]any); ok {
+					idx := st.index
+					if idx < 0 {
+						idx += len(arr)
+					}
+					if idx >= 0 && idx < len(arr) {
+						next = append(next, arr[idx])
+					}
+				}
+			default:
+				if m, ok := c.(map[string]any); ok {
+					if x, ok := m[st.key]; ok {
+						next = append(next, x)
+					}
+				}
+			}
+		}
+		cur = next
+	}
+	return cur
+}
+
+// recordModel は JSON 出力と同じ形のレコードモデルを返す
+func recordModel(rec *Record) (any, error) {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func formatQueryValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+type queryWriter struct {
+	w     *bufio.Writer
+	steps []pathStep
+}
+
+func (q *queryWriter) Write(rec *Record, err error) error {
+	if err != nil {
+		return nil
+	}
+	model, e := recordModel(rec)
+	if e != nil {
+		return e
+	}
+	for _, v := range evalPath(model, q.steps) {
+		if _, e := q.w.WriteString(formatQueryValue(v) + "\n"); e != nil {
+			return e
+		}
+	}
+	return q.w.Flush()
+}
+
+func (q *queryWriter) Flush() error { return q.w.Flush() }
+
+// fieldsWriter は -field / -fields の値だけを書き出す。
+// 1項目なら値を1行ずつ、複数項目ならタブ区切りで1件1行にする
+type fieldsWriter struct {
+	w      *bufio.Writer
+	fields []string
+}
+
+func (f *fieldsWriter) Write(rec *Record, err error) error {
+	if err != nil {
+		return nil
+	}
+	var line string
+	if len(f.fields) == 1 {
+		vals, _ := fieldValues(rec, f.fields[0])
+		if len(vals) == 0 {
+			return nil
+		}
+		line = strings.Join(vals, "\n")
+	} else {
+		line = strings.Join(rowValues(rec, nil, f.fields), "\t")
+	}
+	if _, e := f.w.WriteString(line + "\n"); e != nil {
+		return e
+	}
+	return f.w.Flush()
+}
+
+func (f *fieldsWriter) Flush() error { return f.w.Flush() }
+
+func printJSON(rec *Record) ([]string, error) {
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(b), "\n"), nil
+}
@@ -0,0 +1,244 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type Contact struct {
+	Role         string `json:"role,omitempty"`
+	Handle       string `json:"handle,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Division     string `json:"division,omitempty"`
+	Title        string `json:"title,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Fax          string `json:"fax,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"`
+	Address      string `json:"address,omitempty"`
+	Country      string `json:"country,omitempty"`
+	WebPage      string `json:"web_page,omitempty"`
+	Updated      string `json:"updated,omitempty"`
+}
+
+type Hop struct {
+	Server string `json:"server"`
+	Query  string `json:"query"`
+	Raw    string `json:"-"`
+}
+
+type Record struct {
+	Query            string    `json:"query"`
+	Domain           string    `json:"domain,omitempty"`
+	Registrar        string    `json:"registrar,omitempty"`
+	RegistrarURL     string    `json:"registrar_url,omitempty"`
+	RegistrarIANAID  string    `json:"registrar_iana_id,omitempty"`
+	WhoisServer      string    `json:"whois_server,omitempty"`
+	Organization     string    `json:"organization,omitempty"`
+	OrganizationType string    `json:"organization_type,omitempty"`
+	Created          time.Time `json:"created"`
+	Updated          time.Time `json:"updated"`
+	Expiry           time.Time `json:"expiry"`
+	Status           []string  `json:"status,omitempty"`
+	NameServers      []string  `json:"nameservers,omitempty"`
+	DNSSEC           string    `json:"dnssec,omitempty"`
+	Contacts         []Contact `json:"contacts,omitempty"`
+	Fields           []KV      `json:"-"`
+	Chain            []Hop     `json:"chain,omitempty"`
+	Raw              string    `json:"-"`
+	// ReferralErr は参照先への問い合わせの失敗（Raw は最初のサーバの応答のまま）
+	ReferralErr error `json:"-"`
+}
+
+func (r *Record) contact(role string) *Contact {
+	for i := range r.Contacts {
+		if r.Contacts[i].Role == role {
+			return &r.Contacts[i]
+		}
+	}
+	r.Contacts = append(r.Contacts, Contact{Role: role})
+	return &r.Contacts[len(r.Contacts)-1]
+}
+
+func (r *Record) addStatus(s string) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	// EPP コードは末尾の ICANN URL を落として正規の表記にそろえる
+	if code, ok := eppCode(s); ok {
+		s = code
+	}
+	for _, cur := range r.Status {
+		if strings.EqualFold(cur, s) {
+			return
+		}
+	}
+	r.Status = append(r.Status, s)
+}
+
+func (r *Record) addNameServer(s string) {
+	s = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "."))
+	if s == "" {
+		return
+	}
+	for _, cur := range r.NameServers {
+		if cur == s {
+			return
+		}
+	}
+	r.NameServers = append(r.NameServers, s)
+}
+
+var contactRoles = map[string]string{
+	"registrant": "registrant",
+	"admin":      "admin",
+	"tech":       "tech",
+	"billing":    "billing",
+}
+
+func parseRecord(raw string) *Record {
+	if isJPRSResponse(raw) {
+		return parseJPRS(raw)
+	}
+
+	rec := &Record{Raw: raw}
+	for _, line := range strings.Split(raw, "\n") {
+		l := strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if l == "" || strings.HasPrefix(l, "%") || strings.HasPrefix(l, "#") || strings.HasPrefix(l, ">>>") {
+			continue
+		}
+		parts := strings.SplitN(l, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+		if key == "" || val == "" {
+			continue
+		}
+		rec.Fields = append(rec.Fields, KV{Key: key, Val: val})
+
+		switch strings.ToLower(key) {
+		case "domain name", "domain":
+			if rec.Domain == "" {
+				rec.Domain = strings.ToLower(val)
+			}
+		case "registrar", "registrar name", "sponsoring registrar":
+			if rec.Registrar == "" {
+				rec.Registrar = val
+			}
+		case "registrar url":
+			rec.RegistrarURL = val
+		case "registrar iana id":
+			rec.RegistrarIANAID = val
+		case "registrar whois server", "whois server", "whois":
+			if rec.WhoisServer == "" {
+				rec.WhoisServer = val
+			}
+		case "creation date", "created", "created on", "registered on", "registration time":
+			if t, ok := parseDate(val); ok && rec.Created.IsZero() {
+				rec.Created = t
+			}
+		case "updated date", "last updated", "last-update", "changed", "last modified":
+			if t, ok := parseDate(val); ok && rec.Updated.IsZero() {
+				rec.Updated = t
+			}
+		case "registry expiry date", "registrar registration expiration date", "expiry date", "expires", "expires on", "expiration date", "paid-till":
+			if t, ok := parseDate(val); ok && rec.Expiry.IsZero() {
+				rec.Expiry = t
+			}
+		case "domain status", "status", "state":
+			rec.addStatus(val)
+		case "name server", "nserver", "nameserver", "name servers":
+			rec.addNameServer(strings.Fields(val)[0])
+		case "dnssec":
+			rec.DNSSEC = val
+		default:
+			parseContactField(rec, key, val)
+		}
+	}
+	return rec
+}
+
+// 未登録の名前に対するレジストリの定型文（小文字）
+var notFoundMarkers = []string{
+	"no match", "not found", "no data found", "no entries found", "no matching record",
+	"no object found", "object does not exist", "nothing found", "no such domain",
+	"is available for registration", "status: free", "status: available",
+}
+
+// availability は応答から名前の登録状況を判定する（registered / available / unknown）。
+// 問い合わせや参照先の失敗、IANA の TLD の情報で終わった応答、制限超過などどちらとも読めない応答は unknown
+func availability(rec *Record, err error) string {
+	if err != nil || rec == nil || rec.ReferralErr != nil {
+		return "unknown"
+	}
+	if n := len(rec.Chain); n > 0 && sameServer(rec.Chain[n-1].Server, defaultWhoisServer) {
+		return "unknown"
+	}
+	if rec.Registrar != "" || !rec.Created.IsZero() || len(rec.NameServers) > 0 {
+		return "registered"
+	}
+	low := strings.ToLower(rec.Raw)
+	for _, m := range notFoundMarkers {
+		if strings.Contains(low, m) {
+			return "available"
+		}
+	}
+	if rec.Domain != "" {
+		return "registered"
+	}
+	return "unknown"
+}
+
+var contactSubfields = map[string]bool{
+	"name": true, "organization": true, "organisation": true, "email": true, "phone": true,
+	"fax": true, "postal code": true, "street": true, "city": true, "state/province": true, "country": true,
+}
+
+var registryIDRe = regexp.MustCompile(`(?i)^registry (registrant|admin|tech|billing) id$`)
+
+func parseContactField(rec *Record, key, val string) {
+	if m := registryIDRe.FindStringSubmatch(key); m != nil {
+		rec.contact(contactRoles[strings.ToLower(m[1])]).Handle = val
+		return
+	}
+	fields := strings.SplitN(key, " ", 2)
+	if len(fields) != 2 {
+		return
+	}
+	role, ok := contactRoles[strings.ToLower(fields[0])]
+	if !ok || !contactSubfields[strings.ToLower(fields[1])] {
+		return
+	}
+	c := rec.contact(role)
+	switch strings.ToLower(fields[1]) {
+	case "name":
+		c.Name = val
+	case "organization", "organisation":
+		c.Organization = val
+	case "email":
+		c.Email = val
+	case "phone":
+		c.Phone = val
+	case "fax":
+		c.Fax = val
+	case "postal code":
+		c.PostalCode = val
+	case "street", "city", "state/province":
+		if c.Address == "" {
+			c.Address = val
+		} else {
+			c.Address += ", " + val
+		}
+	case "country":
+		c.Country = val
+	}
+}
@@ -0,0 +1,418 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const replHistoryLimit = 1000
+
+// replHistory は term.History を実装し、入力した行を履歴ファイルに保存する
+type replHistory struct {
+	lines []string // 古い順
+	path  string
+}
+
+func replHistoryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "whois", "history"), nil
+}
+
+func loadReplHistory() *replHistory {
+	h := &replHistory{}
+	path, err := replHistoryPath()
+	if err != nil {
+		return h
+	}
+	h.path = path
+	if b, err := os.ReadFile(path); err == nil {
+		for _, l := range strings.Split(string(b), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				h.lines = append(h.lines, l)
+			}
+		}
+	}
+	if len(h.lines) > replHistoryLimit {
+		h.lines = h.lines[len(h.lines)-replHistoryLimit:]
+	}
+	return h
+}
+
+func (h *replHistory) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == entry) {
+		return
+	}
+	h.lines = append(h.lines, entry)
+	if len(h.lines) > replHistoryLimit {
+		h.lines = h.lines[1:]
+	}
+	h.save()
+}
+
+func (h *replHistory) Len() int { return len(h.lines) }
+
+// At の 0 は最も新しい行
+func (h *replHistory) At(idx int) string { return h.lines[len(h.lines)-1-idx] }
+
+// save は履歴ファイルを書き直す（失敗しても REPL は続ける）
+func (h *replHistory) save() {
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(h.path, []byte(strings.Join(h.lines, "\n")+"\n"), 0o600)
+}
+
+// 端末の入力は1つのゴルーチンで読み、対話モードと全画面表示で共有する。
+// 読み手が求めたときだけ読むので、全画面表示を閉じた時点で読み取り中だった入力も次の読み手が受け取る
+var (
+	stdinOnce    sync.Once
+	stdinWant    chan struct{}
+	stdinGot     chan stdinChunk
+	stdinMu      sync.Mutex
+	stdinPending bool
+)
+
+type stdinChunk struct {
+	b   []byte
+	err error
+}
+
+// requestStdin は（まだなら）読み取りを依頼し、結果が届くチャネルを返す。受け取ったら stdinReceived を呼ぶ
+func requestStdin() <-chan stdinChunk {
+	stdinOnce.Do(func() {
+		stdinWant = make(chan struct{})
+		stdinGot = make(chan stdinChunk, 1)
+		go func() {
+			for range stdinWant {
+				buf := make([]byte, 256)
+				n, err := os.Stdin.Read(buf)
+				stdinGot <- stdinChunk{b: buf[:n], err: err}
+			}
+		}()
+	})
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	if !stdinPending {
+		stdinPending = true
+		stdinWant <- struct{}{}
+	}
+	return stdinGot
+}
+
+func stdinReceived() {
+	stdinMu.Lock()
+	stdinPending = false
+	stdinMu.Unlock()
+}
+
+// stdinReader は requestStdin を io.Reader として読む
+type stdinReader struct {
+	rest []byte
+}
+
+func (r *stdinReader) Read(p []byte) (int, error) {
+	if len(r.rest) == 0 {
+		c := <-requestStdin()
+		stdinReceived()
+		if len(c.b) == 0 && c.err != nil {
+			return 0, c.err
+		}
+		r.rest = c.b
+	}
+	n := copy(p, r.rest)
+	r.rest = r.rest[n:]
+	return n, nil
+}
+
+// replInput は端末なら行編集と履歴つきで、パイプなら1行ずつ読む
+type replInput struct {
+	fd      int
+	term    *term.Terminal
+	scanner *bufio.Scanner
+}
+
+func newReplInput(history *replHistory) *replInput {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return &replInput{scanner: bufio.NewScanner(os.Stdin)}
+	}
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{&stdinReader{}, os.Stdout}, "")
+	t.History = history
+	if w, h, err := term.GetSize(fd); err == nil && w > 0 {
+		_ = t.SetSize(w, h)
+	}
+	return &replInput{fd: fd, term: t}
+}
+
+// readLine は入力中だけ端末を raw モードにし、検索結果の表示は通常モードで行う
+func (in *replInput) readLine(prompt string) (string, error) {
+	if in.term == nil {
+		if !in.scanner.Scan() {
+			if err := in.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return in.scanner.Text(), nil
+	}
+	state, err := term.MakeRaw(in.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(in.fd, state)
+	in.term.SetPrompt(prompt)
+	line, err := in.term.ReadLine()
+	if errors.Is(err, term.ErrPasteIndicator) {
+		err = nil
+	}
+	return line, err
+}
+
+type repl struct {
+	config  Config
+	opts    lookupOptions
+	mode    string
+	history *replHistory
+	results map[string]*Record // 検索名・サーバ・リファラ追跡ごとの結果（同じ検索は即座に返す）
+	names   []string           // :tui で一覧にする、このセッションで検索した名前
+	recent  []*Record          // :diff 用の直近の結果（新しい順に2件）
+}
+
+// runREPL は whois -i の対話プロンプト。1行に複数の名前を書くと順に検索する
+func runREPL(config Config) int {
+	mode := outputMode(config.DefaultOutput)
+	switch {
+	case *rawFlag:
+		mode = "raw"
+	case *tableFlag:
+		mode = "table"
+	case mode != "raw" && mode != "json" && mode != "table":
+		mode = "conventional"
+	}
+	r := &repl{
+		config:  config,
+		opts:    flagLookupOptions(config),
+		mode:    mode,
+		history: loadReplHistory(),
+		results: map[string]*Record{},
+	}
+	in := newReplInput(r.history)
+	if in.term != nil {
+		fmt.Println(msg("banner"))
+		fmt.Println(msg("repl.welcome"))
+	}
+	prompt := colorize("whois> ", "option", config.Color)
+	for {
+		line, err := in.readLine(prompt)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				fmt.Fprintln(os.Stderr, msg("err.repl", err))
+				return exitError
+			}
+			return exitOK
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			if r.command(line) {
+				return exitOK
+			}
+			continue
+		}
+		for _, name := range strings.Fields(line) {
+			domain, err := normalizeDomain(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			r.lookup(domain, false)
+		}
+	}
+}
+
+func (r *repl) resultKey(name string) string {
+	return fmt.Sprintf("%s|%s|%t|%s", name, r.opts.Server, r.opts.Follow, r.opts.JPRSType)
+}
+
+// fetch は同じ条件で検索済みなら保存した結果を返す（refresh で問い合わせ直す）
+func (r *repl) fetch(name string, refresh bool) (*Record, error) {
+	key := r.resultKey(name)
+	if rec, ok := r.results[key]; ok && !refresh {
+		return rec, nil
+	}
+	opts := r.opts
+	opts.Refresh = refresh
+	rec, err := lookup(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	r.results[key] = rec
+	return rec, nil
+}
+
+func (r *repl) lookup(name string, refresh bool) {
+	rec, err := r.fetch(name, refresh)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.connect", err))
+		return
+	}
+	if !slices.Contains(r.names, name) {
+		r.names = append(r.names, name)
+	}
+	r.recent = append([]*Record{rec}, r.recent...)
+	if len(r.recent) > 2 {
+		r.recent = r.recent[:2]
+	}
+	r.show(rec)
+}
+
+func (r *repl) show(rec *Record) {
+	lines, err := renderRecord(rec, r.mode, r.config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.export", err))
+		return
+	}
+	output(lines, "")
+}
+
+// command は ":" で始まる行を実行し、終了するなら true を返す
+func (r *repl) command(line string) bool {
+	fields := strings.Fields(strings.TrimPrefix(line, ":"))
+	if len(fields) == 0 {
+		return false
+	}
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	switch name {
+	case "q", "quit", "exit":
+		return true
+	case "h", "help", "?":
+		fmt.Println(msg("repl.help"))
+	case "raw", "table", "json", "conventional":
+		// 表示形式を切り替え、直前の結果を描き直す
+		r.mode = name
+		fmt.Println(msg("repl.mode", name))
+		if len(r.recent) > 0 {
+			r.show(r.recent[0])
+		}
+	case "server":
+		r.opts.Server = ""
+		if len(args) > 0 && args[0] != "auto" {
+			r.opts.Server = args[0]
+		}
+		if r.opts.Server == "" {
+			fmt.Println(msg("repl.server_auto"))
+		} else {
+			fmt.Println(msg("repl.server", r.opts.Server))
+		}
+	case "follow":
+		switch {
+		case len(args) == 0:
+			r.opts.Follow = !r.opts.Follow
+		case args[0] == "on":
+			r.opts.Follow = true
+		case args[0] == "off":
+			r.opts.Follow = false
+		default:
+			fmt.Fprintln(os.Stderr, msg("repl.usage", ":follow [on|off]"))
+			return false
+		}
+		fmt.Println(msg("repl.follow", r.opts.Follow))
+	case "diff":
+		r.diff(args)
+	case "refresh":
+		if len(r.recent) == 0 {
+			fmt.Fprintln(os.Stderr, msg("repl.no_result"))
+			return false
+		}
+		r.lookup(r.recent[0].Query, true)
+	case "tui":
+		r.tui()
+	case "history":
+		for i, l := range r.history.lines {
+			fmt.Printf("%5d  %s\n", i+1, l)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, msg("repl.unknown", ":"+name))
+	}
+	return false
+}
+
+// tui はこのセッションで検索した名前を全画面表示で開く（検索済みの結果をそのまま使う）
+func (r *repl) tui() {
+	if len(r.names) == 0 {
+		fmt.Fprintln(os.Stderr, msg("repl.no_result"))
+		return
+	}
+	items := make([]tuiItem, len(r.names))
+	for i, name := range r.names {
+		items[i] = tuiItem{name: name, rec: r.results[r.resultKey(name)]}
+	}
+	if err := runTUI(items, r.config, r.opts); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.tui", err))
+		return
+	}
+	// 全画面表示で検索し直した結果を引き継ぐ
+	for _, it := range items {
+		if it.rec != nil {
+			r.results[r.resultKey(it.name)] = it.rec
+		}
+	}
+}
+
+// diff は直前の2件、または指定した名前どうしを横並びで比較する
+func (r *repl) diff(args []string) {
+	var recs []*Record
+	var errs []error
+	if len(args) == 0 {
+		if len(r.recent) < 2 {
+			fmt.Fprintln(os.Stderr, msg("repl.diff_need"))
+			return
+		}
+		recs = []*Record{r.recent[1], r.recent[0]}
+		errs = make([]error, 2)
+	} else {
+		for _, a := range args {
+			var rec *Record
+			domain, err := normalizeDomain(a)
+			if err == nil {
+				rec, err = r.fetch(domain, false)
+			}
+			recs = append(recs, rec)
+			errs = append(errs, err)
+		}
+	}
+	names := make([]string, len(recs))
+	for i, rec := range recs {
+		if rec != nil {
+			names[i] = rec.Query
+		} else {
+			names[i] = args[i]
+		}
+	}
+	output(renderCompare(names, compareRows(recs, errs, r.config.Lang), tableWidth(), r.config.Color), "")
+}
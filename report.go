@@ -0,0 +1,233 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSON のキー順を保ったまま YAML に変換するためのノード
+type yamlNode struct {
+	kind  byte // 'm': map, 's': seq, 'v': scalar
+	keys  []string
+	vals  []*yamlNode
+	value any
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &yamlNode{kind: 'm'}
+			for dec.More() {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, k.(string))
+				n.vals = append(n.vals, v)
+			}
+			_, err := dec.Token()
+			return n, err
+		case '[':
+			n := &yamlNode{kind: 's'}
+			for dec.More() {
+				v, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.vals = append(n.vals, v)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+	}
+	return &yamlNode{kind: 'v', value: tok}, nil
+}
+
+func yamlScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case string:
+		return yamlString(t)
+	}
+	return fmt.Sprint(v)
+}
+
+func yamlString(s string) string {
+	needQuote := s == "" || strings.TrimSpace(s) != s ||
+		strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t") ||
+		strings.ContainsAny(s[:1], "-?")
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		needQuote = true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		needQuote = true
+	}
+	if !needQuote {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func writeYAML(b *strings.Builder, n *yamlNode, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch n.kind {
+	case 'm':
+		for i, k := range n.keys {
+			v := n.vals[i]
+			switch {
+			case v.kind == 'v':
+				fmt.Fprintf(b, "%s%s: %s\n", pad, yamlString(k), yamlScalar(v.value))
+			case len(v.vals) == 0 && v.kind == 's':
+				fmt.Fprintf(b, "%s%s: []\n", pad, yamlString(k))
+			case len(v.keys) == 0 && v.kind == 'm':
+				fmt.Fprintf(b, "%s%s: {}\n", pad, yamlString(k))
+			default:
+				fmt.Fprintf(b, "%s%s:\n", pad, yamlString(k))
+				writeYAML(b, v, indent+1)
+			}
+		}
+	case 's':
+		for _, v := range n.vals {
+			if v.kind == 'v' {
+				fmt.Fprintf(b, "%s- %s\n", pad, yamlScalar(v.value))
+				continue
+			}
+			// "- " の後ろに最初のキーを続け、残りは字下げする
+			var sub strings.Builder
+			writeYAML(&sub, v, indent+1)
+			text := strings.TrimPrefix(sub.String(), strings.Repeat("  ", indent+1))
+			fmt.Fprintf(b, "%s- %s", pad, text)
+		}
+	}
+}
+
+func toYAML(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	n, err := decodeYAMLNode(dec)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	writeYAML(&sb, n, 0)
+	return sb.String(), nil
+}
+
+type yamlWriter struct {
+	w *bufio.Writer
+}
+
+func (y *yamlWriter) Write(rec *Record, err error) error {
+	if err != nil {
+		return y.writeValue(errorJSON{rec.Query, err.Error()})
+	}
+	return y.writeValue(rec)
+}
+
+// writeValue は v を YAML の1文書として書く
+func (y *yamlWriter) writeValue(v any) error {
+	text, e := toYAML(v)
+	if e != nil {
+		return e
+	}
+	if _, e := y.w.WriteString("---\n" + text); e != nil {
+		return e
+	}
+	return y.w.Flush()
+}
+
+func (y *yamlWriter) Flush() error { return y.w.Flush() }
+
+type markdownWriter struct {
+	w       *bufio.Writer
+	lang    string
+	verbose bool
+	notes   int
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r", "", "\n", "<br>")
+
+func (m *markdownWriter) Write(rec *Record, err error) error {
+	return m.writeRecord(rec, err, nil)
+}
+
+// writeRecord は1件を書く。extra は見出しのすぐ下に表で加える項目（squat・sweep の種類と登録状況）
+func (m *markdownWriter) writeRecord(rec *Record, err error, extra []KV) error {
+	var b strings.Builder
+	name := rec.Query
+	note := ""
+	if len(rec.Chain) > 0 {
+		m.notes++
+		note = fmt.Sprintf("[^%d]", m.notes)
+	}
+	fmt.Fprintf(&b, "## %s%s\n\n", markdownEscaper.Replace(name), note)
+	if len(extra) > 0 {
+		m.writeTable(&b, extra)
+	}
+
+	if err != nil {
+		fmt.Fprintf(&b, "> %s\n\n", markdownEscaper.Replace(msg("compare.error", err)))
+	} else {
+		for _, s := range buildSections(rec, m.lang, m.verbose) {
+			fmt.Fprintf(&b, "### %s\n\n", markdownEscaper.Replace(s.Title))
+			m.writeTable(&b, s.Rows)
+		}
+	}
+
+	// 参照チェーンは脚注として残す
+	if note != "" {
+		var hops []string
+		for _, h := range rec.Chain {
+			hops = append(hops, fmt.Sprintf("`%s` (%s)", h.Server, markdownEscaper.Replace(h.Query)))
+		}
+		fmt.Fprintf(&b, "%s: %s %s\n\n", note, msg("markdown.chain"), strings.Join(hops, " → "))
+	}
+
+	if _, e := m.w.WriteString(b.String()); e != nil {
+		return e
+	}
+	return m.w.Flush()
+}
+
+// writeTable は項目と値の2列の表を書く
+func (m *markdownWriter) writeTable(b *strings.Builder, rows []KV) {
+	fmt.Fprintf(b, "| %s | %s |\n| --- | --- |\n", msg("markdown.item"), msg("markdown.value"))
+	for _, kv := range rows {
+		fmt.Fprintf(b, "| %s | %s |\n", markdownEscaper.Replace(kv.Key), markdownEscaper.Replace(kv.Val))
+	}
+	b.WriteString("\n")
+}
+
+func (m *markdownWriter) Flush() error { return m.w.Flush() }
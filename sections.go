@@ -0,0 +1,232 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type Section struct {
+	ID    string
+	Title string
+	Rows  []KV
+}
+
+var sectionOrder = []string{
+	"domain", "registrar", "dates", "status", "nameservers",
+	"registrant", "admin", "tech", "billing", "contact", "dnssec", "other",
+}
+
+var redactedMarkers = []string{
+	"redacted", "please query the rdds", "data protected", "not disclosed",
+	"withheld", "statutory masking", "gdpr masked", "privacy service",
+}
+
+func isRedacted(val string) bool {
+	low := strings.ToLower(val)
+	for _, m := range redactedMarkers {
+		if strings.Contains(low, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownKey(key string) bool {
+	if hasLabel(key) {
+		return true
+	}
+	keyLower := strings.ToLower(key)
+	commonPatterns := []string{
+		"domain", "registrar", "registrant", "admin", "tech", "billing",
+		"created", "updated", "expires", "expiry", "status", "server", "name",
+		"organization", "organisation", "email", "phone", "fax", "address",
+		"city", "state", "country", "postal", "whois", "url", "iana",
+	}
+	for _, pattern := range commonPatterns {
+		if strings.Contains(keyLower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func classifyField(key string) string {
+	low := strings.ToLower(key)
+	switch low {
+	case "domain name", "domain", "registry domain id", "organization", "organization (kana)", "organization type":
+		return "domain"
+	case "registrant":
+		return "registrant"
+	case "administrative contact", "technical contact":
+		// ハンドルは Contacts 側で表示する
+		return "contact-field"
+	case "domain status", "status", "state":
+		return "status"
+	case "name server", "nserver", "nameserver", "name servers":
+		return "nameservers"
+	case "dnssec", "signing key", "ds record", "ds-rdata":
+		return "dnssec"
+	case "registrar registration expiration date":
+		return "dates"
+	}
+	if registryIDRe.MatchString(key) {
+		return "contact-field"
+	}
+	if fields := strings.SplitN(low, " ", 2); len(fields) == 2 {
+		if _, ok := contactRoles[fields[0]]; ok {
+			return "contact-field"
+		}
+	}
+	if strings.HasPrefix(low, "registrar") || strings.HasPrefix(low, "sponsoring registrar") ||
+		low == "reseller" || low == "whois server" || low == "whois" {
+		return "registrar"
+	}
+	if strings.Contains(low, "date") || strings.Contains(low, "created") || strings.Contains(low, "updated") ||
+		strings.Contains(low, "expir") || low == "changed" || low == "paid-till" || low == "last update" || low == "regdate" {
+		return "dates"
+	}
+	return "other"
+}
+
+func contactRows(c Contact, lang string) []KV {
+	var rows []KV
+	add := func(label, val string) {
+		if val != "" {
+			rows = append(rows, KV{Key: translateLabel(label, lang), Val: val})
+		}
+	}
+	add("Handle", c.Handle)
+	add("Name", c.Name)
+	add("Organization", c.Organization)
+	add("Division", c.Division)
+	add("Title", c.Title)
+	add("Email", c.Email)
+	add("Phone", c.Phone)
+	add("Fax", c.Fax)
+	add("Postal Code", c.PostalCode)
+	add("Postal Address", c.Address)
+	add("Country", c.Country)
+	add("Web Page", c.WebPage)
+	return rows
+}
+
+// 同じキーの行は1つのセルにまとめ、値を改行で連結する
+func appendCollapsed(rows []KV, key, val string) []KV {
+	for i := range rows {
+		if rows[i].Key == key {
+			for _, v := range strings.Split(rows[i].Val, "\n") {
+				if v == val {
+					return rows
+				}
+			}
+			rows[i].Val += "\n" + val
+			return rows
+		}
+	}
+	return append(rows, KV{Key: key, Val: val})
+}
+
+func buildSections(rec *Record, lang string, verbose bool) []Section {
+	rows := map[string][]KV{}
+	dateZone := time.UTC
+	if isJPRSResponse(rec.Raw) {
+		dateZone = jstZone
+	}
+	for _, f := range rec.Fields {
+		id := classifyField(f.Key)
+		// ステータスは rec.Status から EPP コードとして組み立てる
+		if id == "contact-field" || id == "status" {
+			continue
+		}
+		if id == "other" && !verbose && !isKnownKey(f.Key) {
+			continue
+		}
+		val := displayIDN(f.Val)
+		if id == "dates" {
+			val = displayDateIn(f.Val, dateZone)
+		}
+		rows[id] = appendCollapsed(rows[id], translateLabel(f.Key, lang), val)
+	}
+	rows["status"] = statusKVs(rec, lang)
+	for _, c := range rec.Contacts {
+		id := c.Role
+		if id == "" {
+			id = "contact"
+		}
+		for _, kv := range contactRows(c, lang) {
+			rows[id] = appendCollapsed(rows[id], kv.Key, kv.Val)
+		}
+	}
+
+	var sections []Section
+	// 紛らわしい国際化ドメイン名は先頭で警告する
+	if kvs := lookalikeKVs(rec.Query); len(kvs) > 0 {
+		sections = append(sections, Section{ID: "lookalike", Title: msg("section.lookalike"), Rows: kvs})
+	}
+	for _, id := range sectionOrder {
+		r := rows[id]
+		if !verbose {
+			if len(r) == 0 || allRedacted(r) {
+				continue
+			}
+		} else if len(r) == 0 {
+			r = []KV{{Key: "", Val: msg("table.none")}}
+		}
+		sections = append(sections, Section{ID: id, Title: msg("section." + id), Rows: r})
+	}
+	return sections
+}
+
+func allRedacted(rows []KV) bool {
+	for _, kv := range rows {
+		if !isRedacted(kv.Val) {
+			return false
+		}
+	}
+	return true
+}
+
+func renderSectionTable(title string, sections []Section, width int, color bool) []string {
+	if width < 40 {
+		width = 40
+	}
+
+	var all []KV
+	for _, s := range sections {
+		all = append(all, s.Rows...)
+	}
+	// 内容に合わせて縮める場合も、セクション名が収まる幅は確保する
+	fitted := fitTableWidth(title, all, width)
+	for _, s := range sections {
+		fitted = max(fitted, min(width, dispWidth(s.Title)+6))
+	}
+	width = fitted
+	maxKey, valueWidth := columnWidths(all, width)
+
+	b := tableBorder
+	var lines []string
+	lines = append(lines, b.rule(b.tl, b.tr, width)...)
+	lines = append(lines, b.title(title, width))
+	lines = append(lines, b.rule(b.bl, b.br, width)...)
+	var out []string
+	for _, l := range lines {
+		out = append(out, colorize(l, "title", color))
+	}
+	for _, s := range sections {
+		// 罫線を描かない場合はセクションの間を空行で区切る
+		if b.rules {
+			out = append(out, colorize(b.rule(b.tl+b.h+" "+s.Title+" ", b.tr, width)[0], "title", color))
+		} else {
+			out = append(out, "", colorize(b.left()+s.Title, "title", color))
+		}
+		out = append(out, renderKVRows(s.Rows, maxKey, valueWidth, color)...)
+		for _, l := range b.rule(b.bl, b.br, width) {
+			out = append(out, colorize(l, "title", color))
+		}
+	}
+	return out
+}
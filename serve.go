@@ -0,0 +1,63 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// serve は検索結果を HTTP で返す。
+//
+//	GET /lookup?domain=example.com            JSON レコード
+//	GET /lookup?domain=example.com&output=raw 生の WHOIS テキスト
+//	GET /healthz
+func runServe(args []string, config Config) int {
+	opts := flagLookupOptions(config)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/lookup", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("domain")
+		if name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "missing domain parameter"})
+			return
+		}
+		domain, err := normalizeDomain(name)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"query": name, "error": err.Error()})
+			return
+		}
+		rec, err := lookup(domain, opts)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"query": domain, "error": err.Error()})
+			return
+		}
+		if r.URL.Query().Get("output") == "raw" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, rec.Raw)
+			return
+		}
+		writeJSON(w, http.StatusOK, rec)
+	})
+
+	fmt.Fprintln(os.Stderr, msg("serve.listening", *addrFlag))
+	if err := http.ListenAndServe(*addrFlag, mux); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.serve", err))
+		return exitError
+	}
+	return exitOK
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
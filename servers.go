@@ -0,0 +1,177 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ServerConfig は config.json の servers の1項目。
+// Host を省略すると組み込みのサーバに Query / Charset だけを適用する
+type ServerConfig struct {
+	Host    string `json:"host,omitempty"`
+	Query   string `json:"query,omitempty"`   // 例: "=%s", "-T dn,ace %s", "%s/e"
+	Charset string `json:"charset,omitempty"` // 例: "iso-8859-1", "shift_jis"
+}
+
+// "corp": "whois.corp.example:4343" のようにホストだけの指定も受け付ける
+func (s *ServerConfig) UnmarshalJSON(b []byte) error {
+	var host string
+	if err := json.Unmarshal(b, &host); err == nil {
+		*s = ServerConfig{Host: host}
+		return nil
+	}
+	type alias ServerConfig
+	var a alias
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return err
+	}
+	*s = ServerConfig(a)
+	return nil
+}
+
+// config.json の servers（TLD・接尾辞 → 設定）。prepare で設定する
+var serverOverrides map[string]ServerConfig
+
+// config.json の rate_limit。同じサーバへの問い合わせの最小間隔（prepare で設定する）
+var (
+	rateLimit   time.Duration
+	rateMu      sync.Mutex
+	lastQueried = map[string]time.Time{}
+)
+
+// waitRateLimit は addr への前回の問い合わせから rateLimit が経つまで待つ。
+// serve の並行リクエストでも間隔が保たれるよう、次の枠を先に予約してから待つ
+func waitRateLimit(addr string) {
+	if rateLimit <= 0 {
+		return
+	}
+	rateMu.Lock()
+	next := lastQueried[addr].Add(rateLimit)
+	if now := time.Now(); next.Before(now) {
+		next = now
+	}
+	lastQueried[addr] = next
+	rateMu.Unlock()
+	time.Sleep(time.Until(next))
+}
+
+// serverSuffix は "jp" / ".jp" / "example.co.jp" を ".jp" の形にそろえる
+func serverSuffix(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !strings.HasPrefix(key, ".") {
+		key = "." + key
+	}
+	return key
+}
+
+func sameServer(a, b string) bool {
+	return strings.EqualFold(normalizeServer(a), normalizeServer(b))
+}
+
+// matchServerOverride は domain に最も長く一致する接尾辞の設定を返す
+func matchServerOverride(domain string) (ServerConfig, bool) {
+	name := "." + strings.ToLower(strings.TrimSuffix(domain, "."))
+	best := ""
+	for key := range serverOverrides {
+		if suf := serverSuffix(key); strings.HasSuffix(name, suf) && len(suf) > len(serverSuffix(best)) {
+			best = key
+		}
+	}
+	if best == "" {
+		return ServerConfig{}, false
+	}
+	return serverOverrides[best], true
+}
+
+// serverConfigForHost は Host が server と一致する設定を返す（リファラ先にも適用するため）
+func serverConfigForHost(server string) (ServerConfig, bool) {
+	for _, sc := range serverOverrides {
+		if sc.Host != "" && sameServer(sc.Host, server) {
+			return sc, true
+		}
+	}
+	return ServerConfig{}, false
+}
+
+// serverSettings は最初の問い合わせに使う設定を返す。
+// Host のない設定は、組み込みの表にない TLD で最初に問い合わせる IANA には使わず、参照先に使う（referralSettings）
+func serverSettings(server, domain string) ServerConfig {
+	if sc, ok := matchServerOverride(domain); ok && (sameServer(sc.Host, server) || sc.Host == "" && !sameServer(server, defaultWhoisServer)) {
+		return sc
+	}
+	sc, _ := serverConfigForHost(server)
+	return sc
+}
+
+// referralSettings は server から ref へ参照をたどるときの設定を返す
+func referralSettings(ref, server, domain string) ServerConfig {
+	if sc, ok := serverConfigForHost(ref); ok {
+		return sc
+	}
+	if sc, ok := matchServerOverride(domain); ok && sc.Host == "" && sameServer(server, defaultWhoisServer) {
+		return sc
+	}
+	return ServerConfig{}
+}
+
+// describeServer は servers コマンド向けに "host (query: ..., charset: ...)" の形で返す
+func describeServer(host string, sc ServerConfig) string {
+	if host == "" {
+		host = "*"
+	}
+	var opts []string
+	if sc.Query != "" {
+		opts = append(opts, "query: "+sc.Query)
+	}
+	if sc.Charset != "" {
+		opts = append(opts, "charset: "+sc.Charset)
+	}
+	if len(opts) == 0 {
+		return host
+	}
+	return host + " (" + strings.Join(opts, ", ") + ")"
+}
+
+func formatQuery(template, name string) string {
+	return strings.ReplaceAll(template, "%s", name)
+}
+
+func decodeCharset(b []byte, charset string) (string, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return "", fmt.Errorf("charset %q: %w", charset, err)
+	}
+	out, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return "", fmt.Errorf("charset %q: %w", charset, err)
+	}
+	return string(out), nil
+}
+
+func validateServerConfig(key string, sc ServerConfig) error {
+	switch {
+	case sc.Host == "" && sc.Query == "" && sc.Charset == "":
+		return errors.New(msg("err.server_empty", key))
+	case sc.Query != "" && !strings.Contains(sc.Query, "%s"):
+		return errors.New(msg("err.server_query", key, sc.Query))
+	}
+	if sc.Charset != "" {
+		if _, err := htmlindex.Get(sc.Charset); err != nil {
+			return errors.New(msg("err.server_charset", key, sc.Charset))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,461 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// -kinds に指定できる生成方法（この順に候補を並べる）
+var squatKinds = []string{
+	"omission", "insertion", "transposition", "bitflip", "homoglyph", "tld-swap", "hyphenation", "vowel-swap",
+}
+
+// squat の -output csv/tsv の既定の列
+const squatExportFields = "query,domain_unicode,kind,availability,registrar,created,expiry"
+
+// squatCandidate は生成した候補（Name は問い合わせに使う ASCII 表記）
+type squatCandidate struct {
+	Name string
+	Kind string
+}
+
+// QWERTY 配列の行。隣のキーの打ち間違いを insertion に使う
+var keyboardRows = []string{"1234567890-", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// keyboardNeighbors は c の上下左右に隣接するキーを返す（各行は半キーずつ右にずれている）
+func keyboardNeighbors(c rune) []rune {
+	for r, row := range keyboardRows {
+		i := strings.IndexRune(row, c)
+		if i < 0 {
+			continue
+		}
+		var out []rune
+		add := func(r, i int) {
+			if r >= 0 && r < len(keyboardRows) && i >= 0 && i < len(keyboardRows[r]) {
+				out = append(out, rune(keyboardRows[r][i]))
+			}
+		}
+		add(r, i-1)
+		add(r, i+1)
+		add(r-1, i)
+		add(r-1, i+1)
+		add(r+1, i-1)
+		add(r+1, i)
+		return out
+	}
+	return nil
+}
+
+// homoglyphTable は confusablePrototypes を逆引きし、ASCII の原型（"o", "rn" など）→ 似た文字の表にする
+func homoglyphTable() map[string][]string {
+	table := map[string][]string{}
+	for r, proto := range confusablePrototypes() {
+		// ドメイン名は小文字なので原型も小文字で引く（"0" の原型は "O"）
+		proto = strings.ToLower(proto)
+		src := string(r)
+		if hasNonASCII(proto) || src == proto {
+			continue
+		}
+		table[proto] = append(table[proto], src)
+		// m → rn・0 → o のような ASCII どうしは逆向きにも置き換える
+		if r < utf8.RuneSelf {
+			table[src] = append(table[src], proto)
+		}
+	}
+	for k := range table {
+		sort.Strings(table[k])
+	}
+	return table
+}
+
+const squatVowels = "aeiou"
+
+// splitDomain は "example.co.jp" を最初のラベル "example" と残り ".co.jp" に分ける
+func splitDomain(domain string) (label, suffix string, ok bool) {
+	i := strings.Index(domain, ".")
+	if i <= 0 || i == len(domain)-1 {
+		return "", "", false
+	}
+	return domain[:i], domain[i:], true
+}
+
+// validLabel は ASCII のラベルとして登録できる形かを返す（先頭・末尾のハイフンと 3〜4 文字目の "--" は不可）
+func validLabel(l string) bool {
+	if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' || (len(l) >= 4 && l[2:4] == "--") {
+		return false
+	}
+	for i := 0; i < len(l); i++ {
+		c := l[i]
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// squatCandidates は domain に似た名前を kinds の方法で生成する。
+// 問い合わせはせず、重複と元の名前は除く。tlds は tld-swap で置き換える TLD（"com" の形）
+func squatCandidates(domain string, kinds, tlds []string) ([]squatCandidate, error) {
+	domain, err := normalizeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	u := domain
+	if s, ok := unicodeDomain(domain); ok {
+		u = s
+	}
+	label, suffix, ok := splitDomain(u)
+	if !ok {
+		return nil, errors.New(msg("err.squat_domain", domain))
+	}
+	seen := map[string]bool{domain: true}
+	var out []squatCandidate
+	add := func(kind, label, suffix string) {
+		name := label + suffix
+		if hasNonASCII(name) {
+			ascii, err := normalizeDomain(name)
+			if err != nil {
+				return
+			}
+			name = ascii
+		} else if !validLabel(label) {
+			return
+		}
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, squatCandidate{Name: name, Kind: kind})
+		}
+	}
+
+	rs := []rune(label)
+	// replace は rs[i:j] を s に置き換えたラベルを返す
+	replace := func(i, j int, s string) string {
+		return string(rs[:i]) + s + string(rs[j:])
+	}
+	for _, kind := range kinds {
+		switch kind {
+		case "omission":
+			for i := range rs {
+				add(kind, replace(i, i+1, ""), suffix)
+			}
+		case "insertion":
+			// 隣のキーを前後に打ち込んだもの・同じ文字を重ねたもの
+			for i, c := range rs {
+				add(kind, replace(i, i, string(c)), suffix)
+				for _, k := range keyboardNeighbors(c) {
+					add(kind, replace(i, i, string(k)), suffix)
+					add(kind, replace(i+1, i+1, string(k)), suffix)
+				}
+			}
+		case "transposition":
+			for i := 0; i+1 < len(rs); i++ {
+				if rs[i] != rs[i+1] {
+					add(kind, replace(i, i+2, string([]rune{rs[i+1], rs[i]})), suffix)
+				}
+			}
+		case "bitflip":
+			// ASCII の下位7ビットの反転（大文字になるものは DNS では同じ名前なので除く）
+			for i, c := range rs {
+				if c >= utf8.RuneSelf {
+					continue
+				}
+				for bit := 0; bit < 7; bit++ {
+					add(kind, replace(i, i+1, string(c^(1<<bit))), suffix)
+				}
+			}
+		case "homoglyph":
+			table := homoglyphTable()
+			for _, proto := range sortedKeys(table) {
+				for i := range rs {
+					if !strings.HasPrefix(string(rs[i:]), proto) {
+						continue
+					}
+					for _, g := range table[proto] {
+						add(kind, replace(i, i+utf8.RuneCountInString(proto), g), suffix)
+					}
+				}
+			}
+		case "tld-swap":
+			for _, t := range tlds {
+				add(kind, label, "."+t)
+			}
+		case "hyphenation":
+			for i := 1; i < len(rs); i++ {
+				add(kind, replace(i, i, "-"), suffix)
+			}
+		case "vowel-swap":
+			for i, c := range rs {
+				if !strings.ContainsRune(squatVowels, c) {
+					continue
+				}
+				for _, v := range squatVowels {
+					if v != c {
+						add(kind, replace(i, i+1, string(v)), suffix)
+					}
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// parseSquatKinds は -kinds の一覧を検査する
+func parseSquatKinds(s string) ([]string, error) {
+	var kinds []string
+	for _, k := range strings.Split(s, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+		if !slices.Contains(squatKinds, k) {
+			return nil, errors.New(msg("err.unknown_kind", k, strings.Join(squatKinds, ", ")))
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds, nil
+}
+
+// parseTLDList は "com,.net, jp" を ["com", "net", "jp"] にそろえる。空なら組み込みのサーバ表の TLD
+func parseTLDList(s string) []string {
+	var tlds []string
+	if strings.TrimSpace(s) == "" {
+		for _, srv := range whoisServers {
+			tlds = append(tlds, strings.TrimPrefix(srv.suffix, "."))
+		}
+		return tlds
+	}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.Trim(strings.TrimSpace(t), "."))
+		if t != "" && !slices.Contains(tlds, t) {
+			tlds = append(tlds, t)
+		}
+	}
+	return tlds
+}
+
+// availabilityText は登録状況の表示とその色
+func availabilityText(rec *Record, err error) (string, string) {
+	switch availability(rec, err) {
+	case "registered":
+		if rec.Registrar != "" {
+			return msg("availability.registered_by", rec.Registrar), "status-risk"
+		}
+		return msg("availability.registered"), "status-risk"
+	case "available":
+		return msg("availability.available"), "status-ok"
+	}
+	if err != nil {
+		return msg("availability.unknown_err", err), "warning"
+	}
+	return msg("availability.unknown"), "warning"
+}
+
+// runSquat は whois squat。似た名前を生成して検索し、登録済みの候補と登録者を表示する
+func runSquat(args []string, config Config) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, msg("err.squat_usage"))
+		return exitUsage
+	}
+	kinds, err := parseSquatKinds(*kindsFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.flag", err))
+		return exitUsage
+	}
+	domain, err := normalizeDomain(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	tlds, err := resolveTLDs(*tldsFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	cands, err := squatCandidates(domain, kinds, tlds)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	// -dry-run は問い合わせずに候補だけを出力する
+	if *dryRunFlag {
+		var lines []string
+		for _, c := range cands {
+			lines = append(lines, displayIDN(c.Name)+"\t"+c.Kind)
+		}
+		output(lines, *outFile)
+		return exitOK
+	}
+
+	fmt.Fprintln(os.Stderr, msg("squat.checking", len(cands), displayIDN(domain)))
+	check := availabilityCheck{
+		command:       "squat",
+		title:         msg("squat.title", displayIDN(domain)),
+		fields:        squatExportFields,
+		showAvailable: *allFlag,
+	}
+	for _, c := range cands {
+		check.names = append(check.names, c.Name)
+		check.tags = append(check.tags, c.Kind)
+	}
+	return check.run(config)
+}
+
+// squat・sweep は同じサーバへ続けて問い合わせるため、rate_limit が未設定ならこの間隔を空ける
+const defaultCheckRateLimit = "1s"
+
+// availabilityCheck は複数の名前の登録状況の確認（squat・sweep で共用）
+type availabilityCheck struct {
+	command       string   // 出力形式のエラーに表示するコマンド名
+	title         string   // 表の表題
+	names         []string // 問い合わせる ASCII の名前
+	tags          []string // 表の値の先頭に付ける種類（names と同じ順。なければ付けない）
+	fields        string   // -output csv/tsv の既定の列
+	showAvailable bool     // false なら未登録の名前は最後の件数にだけ数える
+}
+
+// run は -output csv などなら bulk と同じ形式で逐次出力し、それ以外は登録状況の表を表示する
+func (c availabilityCheck) run(config Config) int {
+	// 組み込みの表にない TLD は IANA で調べた WHOIS サーバへ直接問い合わせる（名前ごとに IANA を経由しない）
+	if *serverFlag == "" {
+		if err := resolveTLDServers(c.names, *timeoutFlag); err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.tld_servers", err))
+			return exitError
+		}
+	}
+	mode := strings.ToLower(*outputFlag)
+	if exportFormats[mode] {
+		if !flagWasSet("fields") {
+			*fieldsFlag = c.fields
+		}
+		// 検索の失敗は表と同じく availability の unknown として出力するので、終了コードには含めない
+		_, err := exportEach(mode, config, func(fn func(name string)) error {
+			for _, name := range c.names {
+				fn(name)
+			}
+			return nil
+		}, func(rw recordWriter) recordWriter {
+			return &checkWriter{rw: rw, tags: c.tags}
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, msg("err.export", err))
+			return exitError
+		}
+		return exitOK
+	}
+	if mode != "" && mode != "table" {
+		fmt.Fprintln(os.Stderr, msg("err.check_format", c.command, mode))
+		return exitUsage
+	}
+
+	opts := flagLookupOptions(config)
+	counts := map[string]int{}
+	var kvs []KV
+	// 表は最後にまとめて出すので、端末には検索中の名前を1行で上書きして表示する
+	progress := term.IsTerminal(int(os.Stderr.Fd()))
+	for i, name := range c.names {
+		if progress {
+			fmt.Fprint(os.Stderr, "\r\x1b[K"+msg("availability.progress", i+1, len(c.names), displayIDN(name)))
+		}
+		rec, err := lookup(name, opts)
+		state := availability(rec, err)
+		counts[state]++
+		if state == "available" && !c.showAvailable {
+			continue
+		}
+		text, role := availabilityText(rec, err)
+		if rec != nil && !rec.Expiry.IsZero() {
+			text += "  " + msg("availability.expires", formatDay(rec, "expiry"))
+		}
+		if i < len(c.tags) && c.tags[i] != "" {
+			text = "[" + c.tags[i] + "] " + text
+		}
+		kvs = append(kvs, KV{Key: displayIDN(name), Val: text, Role: role})
+	}
+	if progress {
+		fmt.Fprint(os.Stderr, "\r\x1b[K")
+	}
+	kvs = append(kvs, KV{Key: msg("availability.total"), Val: msg("availability.summary",
+		counts["registered"], counts["available"], counts["unknown"])})
+	output(renderTable(c.title, kvs, tableWidth(), config.Color), *outFile)
+	return exitOK
+}
+
+// checkWriter は squat・sweep の -output の各形式に候補の種類（kind）と登録状況（availability）を加える。
+// exportEach は names の順に1件ずつ書くので、n 件目の種類は tags[n]
+type checkWriter struct {
+	rw   recordWriter
+	tags []string
+	n    int
+}
+
+// checkJSON は squat・sweep の ndjson・yaml の1件
+type checkJSON struct {
+	recordJSON
+	Kind         string `json:"kind,omitempty"`
+	Availability string `json:"availability"`
+}
+
+// checkErrorJSON は検索に失敗した名前の ndjson・yaml の1件
+type checkErrorJSON struct {
+	Query        string `json:"query"`
+	Kind         string `json:"kind,omitempty"`
+	Availability string `json:"availability"`
+	Error        string `json:"error"`
+}
+
+func (c *checkWriter) Write(rec *Record, err error) error {
+	kind := ""
+	if c.n < len(c.tags) {
+		kind = c.tags[c.n]
+	}
+	c.n++
+	state := availability(rec, err)
+
+	var v any = checkJSON{rec.toJSON(), kind, state}
+	if err != nil {
+		v = checkErrorJSON{rec.Query, kind, state, err.Error()}
+	}
+	switch w := c.rw.(type) {
+	case *ndjsonWriter:
+		return w.writeValue(v)
+	case *yamlWriter:
+		return w.writeValue(v)
+	case *csvWriter:
+		return w.writeCells(checkCells(rec, err, w.fields, kind))
+	case *tsvWriter:
+		return w.writeCells(checkCells(rec, err, w.fields, kind))
+	case *markdownWriter:
+		var extra []KV
+		if kind != "" {
+			extra = append(extra, KV{Key: msg("availability.kind"), Val: kind})
+		}
+		extra = append(extra, KV{Key: msg("availability.label"), Val: msg("availability." + state)})
+		return w.writeRecord(rec, err, extra)
+	}
+	return c.rw.Write(rec, err)
+}
+
+func (c *checkWriter) Flush() error { return c.rw.Flush() }
+
+// checkCells は csv・tsv の1行の値を返す（kind の列に候補の種類を入れる）
+func checkCells(rec *Record, err error, fields []string, kind string) []string {
+	cells := rowValues(rec, err, fields)
+	for i, f := range fields {
+		if f == "kind" {
+			cells[i] = kind
+		}
+	}
+	return cells
+}
@@ -0,0 +1,215 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// candidateNames は候補を Unicode 表記の名前 → 種類の表にする
+func candidateNames(cands []squatCandidate) map[string]string {
+	names := map[string]string{}
+	for _, c := range cands {
+		name := c.Name
+		if u, ok := unicodeDomain(name); ok {
+			name = u
+		}
+		names[name] = c.Kind
+	}
+	return names
+}
+
+func TestSquatCandidates(t *testing.T) {
+	tests := []struct {
+		domain   string
+		kind     string
+		tlds     []string
+		want     []string // 含まれるべき候補（Unicode 表記）
+		excluded []string // 含まれてはいけない候補
+	}{
+		{"example.com", "omission", nil,
+			[]string{"xample.com", "exmple.com", "exampl.com"}, []string{"example.com"}},
+		{"example.com", "insertion", nil,
+			[]string{"eexample.com", "wexample.com", "ewxample.com", "exaample.com"}, []string{"example.com"}},
+		{"example.com", "transposition", nil,
+			[]string{"xeample.com", "eaxmple.com", "exampel.com"}, []string{"example.com"}},
+		{"book.com", "transposition", nil,
+			[]string{"obok.com", "boko.com"}, []string{"book.com"}},
+		// 大文字になる反転は DNS では同じ名前なので含めない
+		{"example.com", "bitflip", nil,
+			[]string{"dxample.com", "axample.com"}, []string{"Example.com", "example.com"}},
+		{"example.com", "homoglyph", nil,
+			[]string{"exarnple.com", "exаmple.com", "еxample.com"}, []string{"example.com"}},
+		{"modern.com", "homoglyph", nil,
+			[]string{"modem.com", "rnodern.com", "m0dern.com"}, []string{"modern.com"}},
+		// 複数のラベルからなる接尾辞も最初のラベル以外をまとめて置き換える
+		{"example.co.jp", "tld-swap", []string{"com", "jp", "co.jp"},
+			[]string{"example.com", "example.jp"}, []string{"example.co.jp", "example.co.com"}},
+		{"example.com", "hyphenation", nil,
+			[]string{"e-xample.com", "exampl-e.com"}, []string{"-example.com", "example-.com"}},
+		{"example.com", "vowel-swap", nil,
+			[]string{"axample.com", "exomple.com", "examplu.com"}, []string{"example.com"}},
+		// 国際化ドメイン名は Unicode のラベルで生成する
+		{"テスト.jp", "omission", nil,
+			[]string{"スト.jp", "テト.jp", "テス.jp"}, []string{"テスト.jp"}},
+		{"テスト.jp", "tld-swap", []string{"com", "jp"},
+			[]string{"テスト.com"}, []string{"テスト.jp"}},
+		// 先頭・末尾のハイフンと 3〜4 文字目の "--" になる候補は除く
+		{"a-bc.com", "omission", nil,
+			[]string{"abc.com", "a-c.com"}, []string{"-bc.com"}},
+		{"ab-c.com", "omission", nil,
+			[]string{"abc.com", "b-c.com"}, []string{"ab-.com"}},
+		{"ab-c.com", "hyphenation", nil,
+			[]string{"a-b-c.com"}, []string{"ab--c.com"}},
+	}
+	for _, tt := range tests {
+		cands, err := squatCandidates(tt.domain, []string{tt.kind}, tt.tlds)
+		if err != nil {
+			t.Errorf("squatCandidates(%q, %s) failed: %v", tt.domain, tt.kind, err)
+			continue
+		}
+		names := candidateNames(cands)
+		for _, w := range tt.want {
+			if kind, ok := names[w]; !ok {
+				t.Errorf("squatCandidates(%q, %s) lacks %q", tt.domain, tt.kind, w)
+			} else if kind != tt.kind {
+				t.Errorf("squatCandidates(%q, %s): %q has kind %q", tt.domain, tt.kind, w, kind)
+			}
+		}
+		for _, x := range tt.excluded {
+			if _, ok := names[x]; ok {
+				t.Errorf("squatCandidates(%q, %s) contains %q", tt.domain, tt.kind, x)
+			}
+		}
+	}
+}
+
+func TestSquatCandidatesAllKinds(t *testing.T) {
+	for _, domain := range []string{"example.com", "ab-c.co.jp", "テスト.jp", "xn--zckzah.jp", "テ-スト.jp"} {
+		ascii, err := normalizeDomain(domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cands, err := squatCandidates(domain, squatKinds, []string{"com", "net", "jp"})
+		if err != nil {
+			t.Errorf("squatCandidates(%q) failed: %v", domain, err)
+			continue
+		}
+		if len(cands) == 0 {
+			t.Errorf("squatCandidates(%q) returned no candidates", domain)
+		}
+		seen := map[string]bool{}
+		for _, c := range cands {
+			if c.Name == ascii {
+				t.Errorf("squatCandidates(%q) contains the original name", domain)
+			}
+			if seen[c.Name] {
+				t.Errorf("squatCandidates(%q) contains %q twice", domain, c.Name)
+			}
+			seen[c.Name] = true
+			// 問い合わせに使う名前は ASCII で、最初のラベルは登録できる形
+			if hasNonASCII(c.Name) {
+				t.Errorf("squatCandidates(%q): %q is not ASCII", domain, c.Name)
+			}
+			label, _, _ := splitDomain(c.Name)
+			if u, ok := unicodeDomain(c.Name); ok {
+				label, _, _ = splitDomain(u)
+				if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+					t.Errorf("squatCandidates(%q): %q (%s) has an invalid label", domain, u, c.Kind)
+				}
+			} else if !validLabel(label) {
+				t.Errorf("squatCandidates(%q): %q (%s) has an invalid label", domain, c.Name, c.Kind)
+			}
+		}
+	}
+}
+
+func TestSquatCandidatesInvalidDomain(t *testing.T) {
+	for _, domain := range []string{"com", "example.", ".com", "xn--a.com"} {
+		if _, err := squatCandidates(domain, squatKinds, nil); err == nil {
+			t.Errorf("squatCandidates(%q) succeeded, want error", domain)
+		}
+	}
+}
+
+func TestValidLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		want  bool
+	}{
+		{"example", true},
+		{"ex-ample", true},
+		{"0day", true},
+		{"xn--zckzah", false}, // 3〜4 文字目の "--"
+		{"ab--c", false},
+		{"a--b", true},
+		{"-example", false},
+		{"example-", false},
+		{"Example", false},
+		{"ex_ample", false},
+		{"", false},
+		{strings.Repeat("a", 63), true},
+		{strings.Repeat("a", 64), false},
+	}
+	for _, tt := range tests {
+		if got := validLabel(tt.label); got != tt.want {
+			t.Errorf("validLabel(%q) = %v, want %v", tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestParseSquatKinds(t *testing.T) {
+	kinds, err := parseSquatKinds(" Omission, ,tld-swap ")
+	if err != nil || strings.Join(kinds, ",") != "omission,tld-swap" {
+		t.Errorf("parseSquatKinds = %v, %v", kinds, err)
+	}
+	if _, err := parseSquatKinds("omission,typo"); err == nil {
+		t.Error("parseSquatKinds accepted an unknown kind")
+	}
+}
+
+func TestCheckWriter(t *testing.T) {
+	recs := []*Record{
+		{Query: "exmple.com", Domain: "exmple.com", Registrar: "Typo Holdings"},
+		{Query: "exarnple.com"},
+	}
+	errs := []error{nil, errors.New("connection refused")}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"ndjson", []string{
+			`"kind":"omission","availability":"registered"}`,
+			`{"query":"exarnple.com","kind":"homoglyph","availability":"unknown","error":"connection refused"}`,
+		}},
+		{"yaml", []string{"kind: omission\navailability: registered\n", "kind: homoglyph\navailability: unknown\n"}},
+		{"csv", []string{"query,kind,availability\n", "exmple.com,omission,registered\n", "exarnple.com,homoglyph,unknown\n"}},
+		{"tsv", []string{"exmple.com\tomission\tregistered\n", "exarnple.com\thomoglyph\tunknown\n"}},
+		{"markdown", []string{"| omission |", "| registered |", "| homoglyph |", "| unknown |"}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := &checkWriter{
+			rw:   newRecordWriter(tt.format, &buf, []string{"query", "kind", "availability"}, "en"),
+			tags: []string{"omission", "homoglyph"},
+		}
+		for i, rec := range recs {
+			if err := w.Write(rec, errs[i]); err != nil {
+				t.Fatalf("%s: Write: %v", tt.format, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("%s: Flush: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s output lacks %q:\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// IANA が公開しているルートゾーンの TLD 一覧（-tlds all）
+const ianaTLDListURL = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+
+// 取得した一覧はキャッシュディレクトリに保存し、この期間は取り直さない
+const (
+	tldListFile   = "tlds-alpha-by-domain.txt"
+	tldListMaxAge = 7 * 24 * time.Hour
+)
+
+// 組み込みの表にない TLD の WHOIS サーバ（IANA の refer:）も一覧と同じディレクトリに保存する。
+// サーバはめったに変わらないので、一覧より長く使う
+const (
+	tldServerFile   = "tld-whois-servers.tsv"
+	tldServerMaxAge = 30 * 24 * time.Hour
+)
+
+// tldReferrals は IANA から調べた TLD → WHOIS サーバ（空ならその TLD に WHOIS サーバはない）。
+// squat・sweep が resolveTLDServers で埋め、getWhoisServer が組み込みの表の次に引く
+var tldReferrals = map[string]string{}
+
+// sweep の -output csv/tsv の既定の列
+const sweepExportFields = "query,availability,registrar,expiry"
+
+// rootZoneTLDs はルートゾーンの TLD を小文字で返す。
+// 保存した一覧が古ければ取り直し、取得できなければ古い一覧をそのまま使う
+func rootZoneTLDs(timeout time.Duration) ([]string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, tldListFile)
+	if fi, err := os.Stat(path); err != nil || time.Since(fi.ModTime()) > tldListMaxAge {
+		if ferr := fetchTLDList(path, timeout); ferr != nil {
+			if err != nil {
+				return nil, ferr
+			}
+			fmt.Fprintln(os.Stderr, msg("sweep.stale_list", ferr))
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var tlds []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tlds = append(tlds, line)
+	}
+	return tlds, scanner.Err()
+}
+
+// fetchTLDList は IANA の一覧を取得して path に保存する
+func fetchTLDList(path string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(ianaTLDListURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", ianaTLDListURL, resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// resolveTLDs は -tlds を TLD の一覧にする。
+// 空なら組み込みのサーバ表、"all" なら組み込みの表に続けてルートゾーンのすべての TLD
+func resolveTLDs(spec string) ([]string, error) {
+	if !strings.EqualFold(strings.TrimSpace(spec), "all") {
+		return parseTLDList(spec), nil
+	}
+	tlds := parseTLDList("")
+	root, err := rootZoneTLDs(*timeoutFlag)
+	if err != nil {
+		return nil, errors.New(msg("err.tld_list", err))
+	}
+	for _, t := range root {
+		if !slices.Contains(tlds, t) {
+			tlds = append(tlds, t)
+		}
+	}
+	return tlds, nil
+}
+
+// runSweep は whois sweep。1つのラベルを TLD ごとに検索し、登録状況・登録者・有効期限を表示する
+func runSweep(args []string, config Config) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, msg("err.sweep_usage"))
+		return exitUsage
+	}
+	label, err := normalizeDomain(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if strings.Contains(label, ".") || (!hasNonASCII(args[0]) && !validLabel(label)) {
+		fmt.Fprintln(os.Stderr, msg("err.sweep_label", args[0]))
+		return exitUsage
+	}
+	tlds, err := resolveTLDs(*tldsFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	fmt.Fprintln(os.Stderr, msg("sweep.checking", displayIDN(label), len(tlds)))
+	check := availabilityCheck{
+		command:       "sweep",
+		title:         msg("sweep.title", displayIDN(label)),
+		fields:        sweepExportFields,
+		showAvailable: true,
+	}
+	for _, t := range tlds {
+		check.names = append(check.names, label+"."+t)
+	}
+	return check.run(config)
+}
+
+// referredServer は domain の TLD について IANA から調べた WHOIS サーバを返す（ok が false なら調べていない）
+func referredServer(domain string) (server string, ok bool) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	server, ok = tldReferrals[strings.ToLower(tld)]
+	return server, ok
+}
+
+// resolveTLDServers は names の TLD のうち組み込みの表にないものの WHOIS サーバを IANA の refer: から調べ、
+// tldReferrals に入れる。調べた結果は保存し、tldServerMaxAge の間は IANA に問い合わせ直さない
+func resolveTLDServers(names []string, timeout time.Duration) error {
+	var missing []string
+	for _, name := range names {
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			continue
+		}
+		tld := name[i+1:]
+		if !slices.Contains(missing, tld) && getWhoisServer(name) == defaultWhoisServer {
+			missing = append(missing, tld)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, tldServerFile)
+	saved, err := loadTLDServers(path)
+	if err != nil {
+		return err
+	}
+	var todo []string
+	for _, t := range missing {
+		if server, ok := saved[t]; ok {
+			tldReferrals[t] = server
+		} else {
+			todo = append(todo, t)
+		}
+	}
+	if len(todo) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// 先頭行に調べ始めた日時を書き、古くなったらファイルごと調べ直す
+	if len(saved) == 0 {
+		if _, err := fmt.Fprintf(f, "# %s\n", time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(os.Stderr, msg("sweep.resolving", len(todo)))
+	progress := term.IsTerminal(int(os.Stderr.Fd()))
+	for i, t := range todo {
+		if progress {
+			fmt.Fprint(os.Stderr, "\r\x1b[K"+msg("availability.progress", i+1, len(todo), "."+t))
+		}
+		raw, err := queryWhois(defaultWhoisServer, t, "", timeout, false)
+		if err != nil {
+			// 調べられなかった TLD は保存せず、その TLD の名前はこれまでどおり IANA から参照をたどる
+			continue
+		}
+		server := extractReferral(raw)
+		tldReferrals[t] = server
+		// 1件ずつ追記し、途中で止めても調べた分は次回に使う
+		if _, err := fmt.Fprintf(f, "%s\t%s\n", t, server); err != nil {
+			return err
+		}
+	}
+	if progress {
+		fmt.Fprint(os.Stderr, "\r\x1b[K")
+	}
+	return f.Close()
+}
+
+// loadTLDServers は保存した TLD → WHOIS サーバを読む。ファイルがないか古ければ空を返す（古いファイルは消す）
+func loadTLDServers(path string) (map[string]string, error) {
+	servers := map[string]string{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return servers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		t, err := time.Parse(time.RFC3339, strings.TrimPrefix(scanner.Text(), "# "))
+		if err != nil || time.Since(t) > tldServerMaxAge {
+			f.Close()
+			return servers, os.Remove(path)
+		}
+	}
+	for scanner.Scan() {
+		tld, server, ok := strings.Cut(scanner.Text(), "\t")
+		if ok && tld != "" {
+			servers[tld] = server
+		}
+	}
+	return servers, scanner.Err()
+}
@@ -0,0 +1,62 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+func daysUntil(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return int(math.Floor(time.Until(t).Hours() / 24))
+}
+
+func templateFuncs(color bool) template.FuncMap {
+	return template.FuncMap{
+		"daysUntil": daysUntil,
+		"join": func(xs []string, sep string) string {
+			return strings.Join(xs, sep)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"colorize": func(name, s string) string {
+			return colorize(s, name, color)
+		},
+	}
+}
+
+// -template は config.json の templates に定義された名前、またはファイルパスを受け付ける
+func loadTemplate(format, templateArg string, config Config) (*template.Template, error) {
+	text := format
+	name := "format"
+	if templateArg != "" {
+		name = templateArg
+		if t, ok := config.Templates[templateArg]; ok {
+			text = t
+		} else {
+			b, err := os.ReadFile(templateArg)
+			if err != nil {
+				return nil, err
+			}
+			text = string(b)
+		}
+	}
+	return template.New(name).Funcs(templateFuncs(config.Color)).Parse(text)
+}
+
+func renderTemplate(t *template.Template, rec *Record) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, rec); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
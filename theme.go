@@ -0,0 +1,347 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 端末が表示できる色数
+const (
+	colorNone      = iota
+	colorBasic     // 16色
+	color256       // 256色
+	colorTrueColor // 24bit
+)
+
+// themeRoles は colorize で使う役割（テーマで色を指定できるもの）
+var themeRoles = []string{
+	"label", "value", "title", "warning", "error", "status-ok", "status-risk",
+	"version", "copyright", "usage", "option",
+}
+
+// 組み込みのテーマ。値は色指定（属性・色名・0-255・#rrggbb を空白区切りで並べ、背景色は bg: を付ける）
+var builtinThemes = map[string]map[string]string{
+	"dark": {
+		"label":       "bold blue",
+		"value":       "bold white",
+		"title":       "bold green",
+		"warning":     "bold yellow",
+		"error":       "bold red",
+		"status-ok":   "green",
+		"status-risk": "bold red",
+		"version":     "bold cyan",
+		"copyright":   "yellow",
+		"usage":       "bold magenta",
+		"option":      "green",
+	},
+	// 明るい背景では白・黄色・シアンが読めないので、前景色は既定のままか濃い色にする
+	"light": {
+		"label":       "bold blue",
+		"value":       "bold",
+		"title":       "bold green",
+		"warning":     "bold magenta",
+		"error":       "bold red",
+		"status-ok":   "green",
+		"status-risk": "bold red",
+		"version":     "bold blue",
+		"copyright":   "magenta",
+		"usage":       "bold magenta",
+		"option":      "green",
+	},
+	"high-contrast": {
+		"label":       "bold bright-cyan",
+		"value":       "bold",
+		"title":       "bold bright-green",
+		"warning":     "bold black bg:bright-yellow",
+		"error":       "bold bright-white bg:red",
+		"status-ok":   "bold bright-green",
+		"status-risk": "bold bright-white bg:red",
+		"version":     "bold bright-cyan",
+		"copyright":   "bright-yellow",
+		"usage":       "bold bright-magenta",
+		"option":      "bold bright-green",
+	},
+	// 色を使わず、太字・下線・反転だけで区別する
+	"monochrome": {
+		"label":       "bold",
+		"value":       "",
+		"title":       "bold",
+		"warning":     "bold underline",
+		"error":       "bold reverse",
+		"status-ok":   "",
+		"status-risk": "bold reverse",
+		"version":     "bold",
+		"copyright":   "",
+		"usage":       "bold",
+		"option":      "",
+	},
+}
+
+const defaultTheme = "dark"
+
+// 表示に使うテーマ（役割 → SGR 引数）と端末の色数。prepare で設定と端末から決め直す
+var (
+	activeTheme, _ = loadTheme(defaultTheme, nil, colorBasic)
+	colorLevel     = colorBasic
+)
+
+// paint は SGR 引数で s を囲む（空なら何もしない）
+func paint(s, sgr string) string {
+	if sgr == "" {
+		return s
+	}
+	return "\033[" + sgr + "m" + s + "\033[0m"
+}
+
+func themeNames(custom map[string]map[string]string) []string {
+	names := sortedKeys(builtinThemes)
+	for _, name := range sortedKeys(custom) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// themeSpecs はテーマの色指定を返す。独自テーマは base（省略時は dark）の組み込みテーマに重ねる
+func themeSpecs(name string, custom map[string]map[string]string) (map[string]string, error) {
+	if name == "" {
+		name = defaultTheme
+	}
+	def, ok := custom[name]
+	if !ok {
+		b, ok := builtinThemes[name]
+		if !ok {
+			return nil, errors.New(msg("err.unknown_theme", name, strings.Join(themeNames(custom), ", ")))
+		}
+		return b, nil
+	}
+	base := def["base"]
+	if base == "" {
+		base = defaultTheme
+	}
+	specs := map[string]string{}
+	b, ok := builtinThemes[base]
+	if !ok {
+		return nil, errors.New(msg("err.unknown_base_theme", name, base))
+	}
+	for role, spec := range b {
+		specs[role] = spec
+	}
+	for role, spec := range def {
+		if role == "base" || strings.HasPrefix(role, "_") {
+			continue
+		}
+		if !slices.Contains(themeRoles, role) {
+			return nil, errors.New(msg("err.unknown_role", name, role, strings.Join(themeRoles, ", ")))
+		}
+		specs[role] = spec
+	}
+	return specs, nil
+}
+
+// loadTheme は色指定を端末の色数に合わせた SGR 引数に変換する
+func loadTheme(name string, custom map[string]map[string]string, level int) (map[string]string, error) {
+	specs, err := themeSpecs(name, custom)
+	if err != nil {
+		return nil, err
+	}
+	theme := map[string]string{}
+	for role, spec := range specs {
+		sgr, err := parseColorSpec(spec, level)
+		if err != nil {
+			return nil, errors.New(msg("err.theme_color", name, role, err))
+		}
+		theme[role] = sgr
+	}
+	return theme, nil
+}
+
+var (
+	sgrAttrs = map[string]string{
+		"bold": "1", "dim": "2", "italic": "3", "underline": "4", "blink": "5", "reverse": "7", "strike": "9",
+	}
+	basicColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+)
+
+// parseColorSpec は "bold 208 bg:#202020" のような色指定を SGR 引数（"1;38;5;208;48;5;234" など）にする
+func parseColorSpec(spec string, level int) (string, error) {
+	var params []string
+	for _, tok := range strings.Fields(strings.ToLower(spec)) {
+		if a, ok := sgrAttrs[tok]; ok {
+			params = append(params, a)
+			continue
+		}
+		if tok == "none" || tok == "default" {
+			continue
+		}
+		bg := strings.HasPrefix(tok, "bg:")
+		p, err := colorParam(strings.TrimPrefix(tok, "bg:"), bg, level)
+		if err != nil {
+			return "", err
+		}
+		if p != "" {
+			params = append(params, p)
+		}
+	}
+	return strings.Join(params, ";"), nil
+}
+
+// colorParam は1つの色を、端末が表示できる範囲に落として SGR 引数にする
+func colorParam(c string, bg bool, level int) (string, error) {
+	base := 30
+	if bg {
+		base = 40
+	}
+	name, bright := strings.CutPrefix(c, "bright-")
+	if i := slices.Index(basicColors, name); i >= 0 {
+		if bright {
+			return strconv.Itoa(base + 60 + i), nil
+		}
+		return strconv.Itoa(base + i), nil
+	}
+	var r, g, b int
+	idx := -1
+	switch {
+	case strings.HasPrefix(c, "#") && len(c) == 7:
+		v, err := strconv.ParseUint(c[1:], 16, 32)
+		if err != nil {
+			return "", errors.New(msg("err.invalid_color", c))
+		}
+		r, g, b = int(v>>16), int(v>>8&0xff), int(v&0xff)
+	default:
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 0 || n > 255 {
+			return "", errors.New(msg("err.invalid_color", c))
+		}
+		idx = n
+		r, g, b = xtermRGB(n)
+	}
+	switch {
+	case level >= colorTrueColor && idx < 0:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b), nil
+	case level >= color256:
+		if idx < 0 {
+			idx = rgbTo256(r, g, b)
+		}
+		return fmt.Sprintf("%d;5;%d", base+8, idx), nil
+	case level >= colorBasic:
+		return strconv.Itoa(rgbTo16(r, g, b, base)), nil
+	}
+	return "", nil
+}
+
+// xtermRGB は 256 色パレットの番号を RGB にする
+func xtermRGB(n int) (int, int, int) {
+	switch {
+	case n < 16:
+		// 0-15 は端末ごとに異なるので、xterm の既定値で近似する
+		v := 0x80
+		if n >= 8 {
+			v = 0xff
+		}
+		if n == 7 {
+			return 0xc0, 0xc0, 0xc0
+		}
+		if n == 8 {
+			return 0x80, 0x80, 0x80
+		}
+		return (n & 1) * v, (n >> 1 & 1) * v, (n >> 2 & 1) * v
+	case n < 232:
+		steps := []int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return steps[n/36], steps[n/6%6], steps[n%6]
+	}
+	v := 8 + (n-232)*10
+	return v, v, v
+}
+
+// rgbTo256 は RGB を 6x6x6 の色立方体か灰色階調のうち近いほうにする
+func rgbTo256(r, g, b int) int {
+	cube := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	ci := 16 + 36*cube(r) + 6*cube(g) + cube(b)
+	avg := (r + g + b) / 3
+	gi := 232 + min(23, max(0, (avg-3)/10))
+	cr, cg, cb := xtermRGB(ci)
+	gr, _, _ := xtermRGB(gi)
+	if colorDist(r, g, b, gr, gr, gr) < colorDist(r, g, b, cr, cg, cb) {
+		return gi
+	}
+	return ci
+}
+
+// rgbTo16 は RGB を基本の16色のうち最も近い色の SGR 番号にする
+func rgbTo16(r, g, b, base int) int {
+	best, bestDist := 0, -1
+	for i := 0; i < 16; i++ {
+		cr, cg, cb := xtermRGB(i)
+		if d := colorDist(r, g, b, cr, cg, cb); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best >= 8 {
+		return base + 60 + best - 8
+	}
+	return base + best
+}
+
+func colorDist(r1, g1, b1, r2, g2, b2 int) int {
+	return (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+}
+
+// terminalColorLevel は COLORTERM / TERM から端末の色数を推定する
+func terminalColorLevel() int {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return colorTrueColor
+	}
+	t := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case t == "dumb":
+		return colorNone
+	case strings.Contains(t, "direct") || strings.Contains(t, "truecolor"):
+		return colorTrueColor
+	case strings.Contains(t, "256color"):
+		return color256
+	case t == "" && runtime.GOOS == "windows" && os.Getenv("WT_SESSION") != "":
+		// Windows Terminal は TERM を設定しない
+		return colorTrueColor
+	}
+	return colorBasic
+}
+
+// forceColorLevel は FORCE_COLOR の値を返す（0/false で無効、1-3 で色数を指定、それ以外は端末から推定）
+func forceColorLevel() (level int, ok bool) {
+	v, ok := os.LookupEnv("FORCE_COLOR")
+	if !ok {
+		return 0, false
+	}
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "0", "false":
+		return colorNone, true
+	case "1":
+		return colorBasic, true
+	case "2":
+		return color256, true
+	case "3":
+		return colorTrueColor, true
+	}
+	return max(colorBasic, terminalColorLevel()), true
+}
@@ -0,0 +1,746 @@
+// 2025 Whois_CLIApp: darui3018823 All rights reserved.
+// All works created by darui3018823 associated with this repository are the intellectual property of darui3018823.
+// Packages and other third-party materials used in this repository are subject to their respective licenses and copyrights.
+
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// tuiItem は左ペインの1行（検索名と、その結果）
+type tuiItem struct {
+	name    string
+	rec     *Record
+	err     error
+	loading bool
+}
+
+type tuiJob struct {
+	index   int
+	refresh bool
+}
+
+type tuiResult struct {
+	index int
+	rec   *Record
+	err   error
+}
+
+// tuiTab は右ペインのタブ。kind は "sections" / "raw" / "chain"、hop は raw のときの参照チェーンの位置
+type tuiTab struct {
+	kind  string
+	hop   int
+	label string
+}
+
+const (
+	tuiFocusList = iota
+	tuiFocusContent
+)
+
+type tui struct {
+	config        Config
+	opts          lookupOptions
+	items         []tuiItem
+	sel, listTop  int
+	focus         int
+	tab, scroll   int
+	width, height int
+
+	search  *regexp.Regexp
+	matches []int // 検索に一致した表示行
+	match   int
+
+	// prompt が空でない間は1行入力を受け付け、Enter で onInput を呼ぶ
+	prompt  string
+	input   []rune
+	onInput func(string)
+
+	status string
+	help   bool
+
+	jobs    chan tuiJob
+	results chan tuiResult
+	out     *bufio.Writer
+}
+
+// runTUICommand は whois tui。名前を省略すると対話モードの入力履歴を一覧にする
+func runTUICommand(args []string, config Config) int {
+	var names []string
+	if err := forEachName(args, *listFileFlag, func(name string) {
+		domain, err := normalizeDomain(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		names = append(names, domain)
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.tui", err))
+		return exitError
+	}
+	if len(names) == 0 {
+		names = historyNames(loadReplHistory())
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, msg("err.tui_usage"))
+		return exitUsage
+	}
+	items := make([]tuiItem, len(names))
+	for i, name := range names {
+		items[i] = tuiItem{name: name}
+	}
+	if err := runTUI(items, config, flagLookupOptions(config)); err != nil {
+		fmt.Fprintln(os.Stderr, msg("err.tui", err))
+		return exitError
+	}
+	return exitOK
+}
+
+// historyNames は入力履歴から検索した名前を新しい順に重複なく返す
+func historyNames(h *replHistory) []string {
+	seen := map[string]bool{}
+	var names []string
+	for i := 0; i < h.Len(); i++ {
+		line := h.At(i)
+		if strings.HasPrefix(line, ":") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, f := range strings.Fields(line) {
+			name, err := normalizeDomain(f)
+			if err != nil {
+				continue
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// runTUI は items を一覧にした全画面表示を開く。結果のない項目は順に検索し、items に書き戻す
+func runTUI(items []tuiItem, config Config, opts lookupOptions) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return errors.New(msg("err.tui_tty"))
+	}
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(inFd, state)
+
+	t := &tui{
+		config:  config,
+		opts:    opts,
+		items:   items,
+		jobs:    make(chan tuiJob, 2*len(items)+8),
+		results: make(chan tuiResult, 2*len(items)+8),
+		out:     bufio.NewWriter(os.Stdout),
+	}
+	t.width, t.height, _ = term.GetSize(outFd)
+
+	// 代替画面に切り替え、カーソルを隠す
+	fmt.Fprint(t.out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+		t.out.Flush()
+	}()
+
+	// 検索は1件ずつ順に行う（同じサーバへの問い合わせがレート制限の間隔で並ぶように）。
+	// 閉じた後は待っている検索を捨てる（:tui から戻った REPL の裏で問い合わせ続けないように）
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case job := <-t.jobs:
+				// jobs と done が同時に受け取れる場合も、閉じた後は検索しない
+				select {
+				case <-done:
+					return
+				default:
+				}
+				opts := t.opts
+				opts.Refresh = job.refresh
+				rec, err := lookup(t.items[job.index].name, opts)
+				select {
+				case t.results <- tuiResult{index: job.index, rec: rec, err: err}:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	for i := range t.items {
+		if t.items[i].rec == nil && t.items[i].err == nil {
+			t.items[i].loading = true
+			t.jobs <- tuiJob{index: i}
+		}
+	}
+
+	tick := time.NewTicker(300 * time.Millisecond)
+	defer tick.Stop()
+
+	dirty := true
+	for {
+		if dirty {
+			t.draw()
+			dirty = false
+		}
+		select {
+		case c := <-requestStdin():
+			stdinReceived()
+			if len(c.b) == 0 && c.err != nil {
+				return nil
+			}
+			for _, k := range parseKeys(c.b) {
+				if t.key(k) {
+					return nil
+				}
+			}
+			dirty = true
+		case r := <-t.results:
+			it := &t.items[r.index]
+			it.rec, it.err, it.loading = r.rec, r.err, false
+			if r.index == t.sel {
+				t.updateMatches()
+			}
+			dirty = true
+		case <-tick.C:
+			// SIGWINCH のない Windows でも追従できるよう、端末サイズはポーリングで確認する
+			if w, h, err := term.GetSize(outFd); err == nil && (w != t.width || h != t.height) {
+				t.width, t.height = w, h
+				dirty = true
+			}
+		}
+	}
+}
+
+var escapeKeys = map[string]string{
+	"[A": "up", "[B": "down", "[C": "right", "[D": "left",
+	"OA": "up", "OB": "down", "OC": "right", "OD": "left",
+	"[H": "home", "[F": "end", "OH": "home", "OF": "end",
+	"[1~": "home", "[7~": "home", "[4~": "end", "[8~": "end",
+	"[5~": "pgup", "[6~": "pgdn", "[3~": "delete", "[Z": "backtab",
+}
+
+// parseKeys は端末の入力をキー名（"up", "pgdn", "enter" など）または1文字に分ける
+func parseKeys(b []byte) []string {
+	var keys []string
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == 0x1b {
+			// CSI（ESC [ ... 終端文字）と SS3（ESC O x）を1つのキーとして読む
+			end := 0
+			switch {
+			case len(s) >= 3 && s[1] == 'O':
+				end = 3
+			case len(s) >= 3 && s[1] == '[':
+				end = 2
+				for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+					end++
+				}
+				end = min(end+1, len(s))
+			}
+			if end == 0 {
+				keys = append(keys, "esc")
+				s = s[1:]
+				continue
+			}
+			if k, ok := escapeKeys[s[1:end]]; ok {
+				keys = append(keys, k)
+			}
+			s = s[end:]
+			continue
+		}
+		switch s[0] {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		case 0x04:
+			keys = append(keys, "ctrl-d")
+		case 0x15:
+			keys = append(keys, "ctrl-u")
+		default:
+			r, size := utf8.DecodeRuneInString(s)
+			keys = append(keys, string(r))
+			s = s[size:]
+			continue
+		}
+		s = s[1:]
+	}
+	return keys
+}
+
+func (t *tui) current() *tuiItem {
+	return &t.items[t.sel]
+}
+
+func (t *tui) tabs() []tuiTab {
+	tabs := []tuiTab{{kind: "sections", label: msg("tui.tab.sections")}}
+	if rec := t.current().rec; rec != nil {
+		for i, hop := range rec.Chain {
+			tabs = append(tabs, tuiTab{kind: "raw", hop: i, label: msg("tui.tab.raw", i+1, hop.Server)})
+		}
+		if len(rec.Chain) == 0 {
+			tabs = append(tabs, tuiTab{kind: "raw", hop: -1, label: msg("tui.tab.raw", 1, "-")})
+		}
+	}
+	return append(tabs, tuiTab{kind: "chain", label: msg("tui.tab.chain")})
+}
+
+func (t *tui) listWidth() int {
+	w := 12
+	for _, it := range t.items {
+		if n := runewidth.StringWidth(displayIDN(it.name)) + 3; n > w {
+			w = n
+		}
+	}
+	return min(w, max(t.width/4, 12))
+}
+
+func (t *tui) contentWidth() int {
+	return max(t.width-t.listWidth()-1, 20)
+}
+
+// 上からタイトル・タブ・区切り線、最下段がステータス行
+func (t *tui) contentHeight() int {
+	return max(t.height-4, 1)
+}
+
+// content は選択中の項目・タブの表示行を返す（幅は右ペインに合わせる）
+func (t *tui) content() []string {
+	width := t.contentWidth()
+	if t.help {
+		return t.wrapLines(strings.Split(msg("tui.help"), "\n"), width)
+	}
+	it := t.current()
+	switch {
+	case it.loading:
+		return []string{msg("tui.loading")}
+	case it.err != nil:
+		return t.wrapLines([]string{msg("err.connect", it.err)}, width)
+	case it.rec == nil:
+		return nil
+	}
+	rec := it.rec
+	tabs := t.tabs()
+	switch tab := tabs[min(t.tab, len(tabs)-1)]; tab.kind {
+	case "raw":
+		raw := rec.Raw
+		if tab.hop >= 0 {
+			raw = rec.Chain[tab.hop].Raw
+		}
+		return t.wrapLines(rawLines(raw), width)
+	case "chain":
+		var lines []string
+		for i, hop := range rec.Chain {
+			lines = append(lines,
+				fmt.Sprintf("%d. %s", i+1, colorize(hop.Server, "label", t.config.Color)),
+				"   "+msg("tui.chain_query", hop.Query),
+				"   "+msg("tui.chain_lines", len(rawLines(hop.Raw))))
+			if i < len(rec.Chain)-1 {
+				lines = append(lines, "   ↓")
+			}
+		}
+		return t.wrapLines(lines, width)
+	}
+	if lines := recordTableWidth(rec, t.config, width); len(lines) > 0 {
+		return lines
+	}
+	return t.wrapLines(rawLines(rec.Raw), width)
+}
+
+// wrapLines は生テキストの字下げを保ったまま表示幅で折り返す
+func (t *tui) wrapLines(lines []string, width int) []string {
+	var out []string
+	for _, l := range lines {
+		l = strings.ReplaceAll(l, "\t", "    ")
+		if dispWidth(l) <= width {
+			out = append(out, l)
+			continue
+		}
+		out = append(out, hardWrap(l, width)...)
+	}
+	return out
+}
+
+func (t *tui) updateMatches() {
+	t.matches, t.match = nil, 0
+	if t.search == nil {
+		return
+	}
+	for i, l := range t.content() {
+		if t.search.MatchString(stripANSI(l)) {
+			t.matches = append(t.matches, i)
+		}
+	}
+}
+
+func (t *tui) selectItem(i int) {
+	if i < 0 || i >= len(t.items) || i == t.sel {
+		return
+	}
+	t.sel, t.scroll = i, 0
+	if t.tab >= len(t.tabs()) {
+		t.tab = 0
+	}
+	t.updateMatches()
+}
+
+func (t *tui) selectTab(i int) {
+	n := len(t.tabs())
+	t.tab, t.scroll, t.help = (i%n+n)%n, 0, false
+	t.updateMatches()
+}
+
+func (t *tui) scrollBy(n int) {
+	limit := max(len(t.content())-t.contentHeight(), 0)
+	t.scroll = min(max(t.scroll+n, 0), limit)
+}
+
+// jumpMatch は現在位置から dir 方向で次に一致する行まで移動する
+func (t *tui) jumpMatch(dir int) {
+	if len(t.matches) == 0 {
+		t.status = msg("tui.no_match")
+		return
+	}
+	t.match = (t.match + dir + len(t.matches)) % len(t.matches)
+	t.scroll = 0
+	t.scrollBy(t.matches[t.match] - t.contentHeight()/3)
+	t.status = msg("tui.match", t.match+1, len(t.matches))
+}
+
+func (t *tui) startPrompt(label, initial string, fn func(string)) {
+	t.prompt, t.input, t.onInput = label, []rune(initial), fn
+}
+
+// key は1つのキー入力を処理し、終了するなら true を返す
+func (t *tui) key(k string) bool {
+	if t.prompt != "" {
+		switch k {
+		case "enter":
+			fn, text := t.onInput, string(t.input)
+			t.prompt, t.input, t.onInput = "", nil, nil
+			fn(strings.TrimSpace(text))
+		case "esc", "ctrl-c":
+			t.prompt, t.input, t.onInput = "", nil, nil
+		case "backspace":
+			if len(t.input) > 0 {
+				t.input = t.input[:len(t.input)-1]
+			}
+		case "ctrl-u":
+			t.input = nil
+		default:
+			if r := []rune(k); len(r) == 1 && r[0] >= ' ' {
+				t.input = append(t.input, r[0])
+			}
+		}
+		return false
+	}
+
+	t.status = ""
+	page := t.contentHeight() - 1
+	switch k {
+	case "q", "ctrl-c", "ctrl-d":
+		return true
+	case "?":
+		t.help, t.scroll = !t.help, 0
+	case "tab":
+		t.focus = 1 - t.focus
+	case "up", "k":
+		if t.focus == tuiFocusList {
+			t.selectItem(t.sel - 1)
+		} else {
+			t.scrollBy(-1)
+		}
+	case "down", "j":
+		if t.focus == tuiFocusList {
+			t.selectItem(t.sel + 1)
+		} else {
+			t.scrollBy(1)
+		}
+	case "home", "g":
+		if t.focus == tuiFocusList {
+			t.selectItem(0)
+		} else {
+			t.scroll = 0
+		}
+	case "end", "G":
+		if t.focus == tuiFocusList {
+			t.selectItem(len(t.items) - 1)
+		} else {
+			t.scrollBy(len(t.content()))
+		}
+	case "pgdn", " ":
+		t.scrollBy(page)
+	case "pgup", "b":
+		t.scrollBy(-page)
+	case "right", "l":
+		t.selectTab(t.tab + 1)
+	case "left", "h", "backtab":
+		t.selectTab(t.tab - 1)
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(k[0] - '1'); i < len(t.tabs()) {
+			t.selectTab(i)
+		}
+	case "/":
+		t.startPrompt(msg("tui.prompt_search"), "", t.doSearch)
+	case "n":
+		t.jumpMatch(1)
+	case "N":
+		t.jumpMatch(-1)
+	case "y":
+		t.startPrompt(msg("tui.prompt_field"), "", t.copyField)
+	case "r":
+		t.requery()
+	case "e":
+		t.startPrompt(msg("tui.prompt_export"), "whois-export.csv", t.export)
+	}
+	return false
+}
+
+// doSearch は生テキストのタブに切り替えて大文字小文字を区別せずに検索する
+func (t *tui) doSearch(text string) {
+	if text == "" {
+		t.search, t.matches = nil, nil
+		return
+	}
+	t.search = regexp.MustCompile("(?i)" + regexp.QuoteMeta(text))
+	if tabs := t.tabs(); tabs[min(t.tab, len(tabs)-1)].kind != "raw" {
+		for i, tab := range tabs {
+			if tab.kind == "raw" {
+				t.tab, t.scroll = i, 0
+				break
+			}
+		}
+	}
+	t.updateMatches()
+	t.match = -1
+	for i, line := range t.matches {
+		if line >= t.scroll {
+			t.match = i - 1
+			break
+		}
+	}
+	t.jumpMatch(1)
+}
+
+// copyField は -field と同じ名前で値を取り出し、OSC 52 で端末のクリップボードに送る
+func (t *tui) copyField(name string) {
+	rec := t.current().rec
+	if rec == nil || name == "" {
+		return
+	}
+	vals, ok := fieldValues(rec, strings.ToLower(name))
+	if !ok {
+		t.status = msg("tui.unknown_field", name, strings.Join(exportFieldNames, ", "))
+		return
+	}
+	text := strings.Join(vals, "\n")
+	fmt.Fprintf(t.out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	t.status = msg("tui.copied", name, text)
+}
+
+func (t *tui) requery() {
+	it := t.current()
+	if it.loading {
+		return
+	}
+	select {
+	case t.jobs <- tuiJob{index: t.sel, refresh: true}:
+		it.loading, it.err = true, nil
+		t.status = msg("tui.requery", it.name)
+	default:
+		t.status = msg("tui.busy")
+	}
+}
+
+// tuiExportFormats は書き出し先の拡張子から形式を決める（不明な拡張子は ndjson）
+var tuiExportFormats = map[string]string{
+	".csv": "csv", ".tsv": "tsv", ".ndjson": "ndjson", ".jsonl": "ndjson",
+	".yaml": "yaml", ".yml": "yaml", ".md": "markdown",
+}
+
+// export は取得済みの全項目を path に書き出す
+func (t *tui) export(path string) {
+	if path == "" {
+		return
+	}
+	format, ok := tuiExportFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		format = "ndjson"
+	}
+	fields, err := parseFieldList(*fieldsFlag)
+	if err != nil {
+		t.status = msg("err.export", err)
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.status = msg("err.export", err)
+		return
+	}
+	defer f.Close()
+	rw := newRecordWriter(format, f, fields, t.config.Lang)
+	n := 0
+	for _, it := range t.items {
+		if it.loading || (it.rec == nil && it.err == nil) {
+			continue
+		}
+		rec := it.rec
+		if rec == nil {
+			rec = &Record{Query: it.name}
+		}
+		if err := rw.Write(rec, it.err); err != nil {
+			t.status = msg("err.export", err)
+			return
+		}
+		n++
+	}
+	if err := rw.Flush(); err != nil {
+		t.status = msg("err.export", err)
+		return
+	}
+	t.status = msg("tui.exported", n, path, format)
+}
+
+// fitWidth は表示幅 width に切り詰め、足りない分を空白で埋める
+func fitWidth(s string, width int) string {
+	if dispWidth(s) > width {
+		s = runewidth.Truncate(stripANSI(s), width, tableBorder.ellipsis())
+	}
+	return padRightByWidth(s, width)
+}
+
+func (t *tui) highlight(line string) string {
+	if t.search == nil || !t.config.Color {
+		return line
+	}
+	plain := stripANSI(line)
+	if !t.search.MatchString(plain) {
+		return line
+	}
+	return t.search.ReplaceAllStringFunc(plain, func(m string) string {
+		return "\x1b[7m" + m + "\x1b[27m"
+	})
+}
+
+func (t *tui) draw() {
+	color := t.config.Color
+	listW, contentW := t.listWidth(), t.contentWidth()
+	rows := make([]string, 0, t.height)
+
+	title := msg("tui.title", displayIDN(t.current().name), t.sel+1, len(t.items))
+	rows = append(rows, colorize(fitWidth(" "+title, t.width), "title", color))
+
+	// 左ペイン: 選択中の項目が見えるように一覧をずらす
+	listH := t.height - 2
+	if t.sel < t.listTop {
+		t.listTop = t.sel
+	} else if t.sel >= t.listTop+listH {
+		t.listTop = t.sel - listH + 1
+	}
+	tabs := t.tabs()
+	tabParts := make([]string, len(tabs))
+	for i, tab := range tabs {
+		label := fmt.Sprintf(" %d:%s ", i+1, tab.label)
+		if i == min(t.tab, len(tabs)-1) && !t.help {
+			if color {
+				label = "\x1b[7m" + label + "\x1b[27m"
+			} else {
+				label = fmt.Sprintf("[%d:%s]", i+1, tab.label)
+			}
+		}
+		tabParts[i] = label
+	}
+	content := t.content()
+	contentH := t.contentHeight()
+	t.scroll = min(t.scroll, max(len(content)-contentH, 0))
+
+	sep := "│"
+	if t.focus == tuiFocusContent {
+		sep = colorize("┃", "option", color)
+	}
+	for row := 0; row < listH; row++ {
+		left := ""
+		if i := t.listTop + row; i < len(t.items) {
+			it := t.items[i]
+			mark := " "
+			switch {
+			case it.loading:
+				mark = "…"
+			case it.err != nil:
+				mark = colorize("!", "error", color)
+			}
+			cursor := " "
+			if i == t.sel && !color {
+				cursor = ">"
+			}
+			left = mark + cursor + runewidth.Truncate(displayIDN(it.name), listW-2, tableBorder.ellipsis())
+			if i == t.sel && color {
+				switch {
+				case t.focus == tuiFocusList:
+					left = "\x1b[7m" + fitWidth(left, listW) + "\x1b[27m"
+				default:
+					left = "\x1b[1m" + fitWidth(left, listW) + "\x1b[22m"
+				}
+			}
+		}
+		var right string
+		switch row {
+		case 0:
+			right = strings.Join(tabParts, "")
+		case 1:
+			right = strings.Repeat("─", contentW)
+		default:
+			if i := t.scroll + row - 2; i < len(content) {
+				right = t.highlight(content[i])
+			}
+		}
+		rows = append(rows, fitWidth(left, listW)+sep+fitWidth(right, contentW))
+	}
+
+	status := t.status
+	switch {
+	case t.prompt != "":
+		status = t.prompt + string(t.input) + "█"
+	case status == "":
+		status = msg("tui.keys")
+		if len(content) > contentH {
+			status += fmt.Sprintf("  %d-%d/%d", t.scroll+1, min(t.scroll+contentH, len(content)), len(content))
+		}
+	}
+	rows = append(rows, colorize(fitWidth(status, t.width), "usage", color))
+
+	fmt.Fprint(t.out, "\x1b[H")
+	for i, r := range rows {
+		fmt.Fprint(t.out, r, "\x1b[0m\x1b[K")
+		if i < len(rows)-1 {
+			fmt.Fprint(t.out, "\r\n")
+		}
+	}
+	t.out.Flush()
+}